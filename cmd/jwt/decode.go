@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// the time claims printed with the human times
+var timeClaims = []string{"iat", "nbf", "exp"}
+
+// decode the token without verifying the signature
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("decode", stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: jwt decode [token]")
+		fmt.Fprintln(stderr, "\nThe token is read from stdin when it is not set, the signature is not verified.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tokenString, err := readToken(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	if strings.Count(tokenString, ".") == 4 {
+		header, err := jwt.GetTokenHeader(tokenString)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(stdout, "Header:")
+		if err := writeJSON(stdout, header); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(stdout, "\nThe token is encrypted (JWE), the claims can not be decoded.")
+		return nil
+	}
+
+	t := jwt.NewToken(jwt.JWTEncoder)
+	if err := t.Parse(tokenString); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "Header:")
+	if err := writeJSONRaw(stdout, t.GetHeaderRaw()); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "\nClaims:")
+	if err := writeJSONRaw(stdout, t.GetClaimsRaw()); err != nil {
+		return err
+	}
+
+	claims, err := t.GetClaims()
+	if err != nil {
+		return err
+	}
+	writeTimes(stdout, claims)
+
+	fmt.Fprintln(stdout, "\nThe signature is not verified, use `jwt verify` to verify it.")
+	return nil
+}
+
+// write the time claims with the human times
+func writeTimes(w io.Writer, claims jwt.MapClaims) {
+	var lines []string
+	for _, name := range timeClaims {
+		if _, ok := claims[name]; !ok {
+			continue
+		}
+
+		date, err := claims.GetNumericDate(name)
+		if err != nil || date == nil {
+			lines = append(lines, fmt.Sprintf("  %s: invalid", name))
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s: %s (%s)", name, date.UTC().Format(time.RFC3339), relativeTime(date.Time)))
+	}
+
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nTimes:")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// return the time relative to now
+func relativeTime(t time.Time) string {
+	d := t.Sub(clock.Now()).Round(time.Second)
+	if d == 0 {
+		return "now"
+	}
+	if d < 0 {
+		return (-d).String() + " ago"
+	}
+
+	return "in " + d.String()
+}
+
+// write the indented raw json
+func writeJSONRaw(w io.Writer, data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// write the value as indented json
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
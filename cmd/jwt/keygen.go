@@ -0,0 +1,88 @@
+package main
+
+import (
+	"crypto"
+	"io"
+
+	"github.com/deatil/go-jwt/jwk"
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// generate a signing key
+func runKeygen(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("keygen", stderr)
+	alg := fs.String("alg", "", "the signing `alg` of the key, like HS256, RS256, ES256 or EdDSA")
+	bits := fs.Int("bits", jwt.DefaultRSAKeySize, "the RSA key `size`, at least 2048")
+	asJWK := fs.Bool("jwk", false, "write the key as a JWK, the HMAC keys are always JWK")
+	kid := fs.String("kid", "", "the JWK `kid`, the SHA-256 thumbprint of an asymmetric key is used when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := checkAlg(*alg); err != nil {
+		return err
+	}
+
+	key, err := generateKey(*alg, *bits)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := key.([]byte); ok || *asJWK {
+		return writeJWK(stdout, key, *alg, *kid)
+	}
+
+	return writePEM(stdout, key)
+}
+
+// generate the key of the alg, the bits is used by the RSA keys
+func generateKey(alg string, bits int) (any, error) {
+	switch jwt.GetSigningMethod(alg).(type) {
+	case *jwt.SignRSA, *jwt.SignRSAPSS:
+		return jwt.GenerateRSAKey(bits)
+	}
+
+	return jwt.GenerateKey(alg)
+}
+
+// write the PKCS #8 private key and the SPKI public key
+func writePEM(w io.Writer, key any) error {
+	privatePEM, err := jwt.MarshalPKCS8PrivateKeyToPEM(key)
+	if err != nil {
+		return err
+	}
+
+	publicPEM, err := jwt.MarshalPublicKeyToPEM(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(privatePEM); err != nil {
+		return err
+	}
+
+	_, err = w.Write(publicPEM)
+	return err
+}
+
+// write the key as a JWK
+func writeJWK(w io.Writer, key any, alg, kid string) error {
+	k, err := jwk.New(key)
+	if err != nil {
+		return err
+	}
+
+	k.Algorithm = alg
+	k.Use = "sig"
+
+	// the thumbprint of a HMAC key is a hash of the secret
+	if kid != "" {
+		k.KeyID = kid
+	} else if _, ok := key.([]byte); !ok {
+		if err := k.SetThumbprintKeyID(crypto.SHA256); err != nil {
+			return err
+		}
+	}
+
+	return writeJSON(w, k)
+}
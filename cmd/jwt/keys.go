@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/deatil/go-jwt/jwk"
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// read the key file, it is a JWK, a PEM key or a DER key, and the raw
+// HMAC secret when hmac is true. The file is never used as a HMAC secret
+// without hmac, so a public key can not be used as the secret.
+// The public key of a private key is returned when private is false.
+func loadKey(path string, private, hmac bool) (any, error) {
+	if path == "" {
+		return nil, errors.New("the -key flag is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if hmac {
+		return data, nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		k, err := jwk.Parse(trimmed)
+		if err != nil {
+			return nil, err
+		}
+
+		// the oct keys are symmetric
+		if !private && k.IsPrivate() && k.KeyType() != jwk.KeyTypeOct {
+			if k, err = k.Public(); err != nil {
+				return nil, err
+			}
+		}
+
+		return k.Key, nil
+	}
+
+	// the text before the PEM block, like the openssl attributes, is skipped
+	if block, _ := pem.Decode(data); block != nil {
+		return parsePEMKey(pem.EncodeToMemory(block), private)
+	}
+
+	if key, err := parseDERKey(data, private); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("the key is not a JWK, PEM or DER key, use -hmac for a raw HMAC secret")
+}
+
+// parse the PEM RSA, ECDSA or Ed25519 key
+func parsePEMKey(data []byte, private bool) (any, error) {
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return publicIfNeeded(rsaKey, private), nil
+	}
+	if ecKey, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		return publicIfNeeded(ecKey, private), nil
+	}
+	if edKey, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		return publicIfNeeded(edKey, private), nil
+	}
+
+	if private {
+		return nil, errors.New("the PEM key is not a RSA, ECDSA or Ed25519 private key")
+	}
+
+	if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return rsaKey, nil
+	}
+	if ecKey, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return ecKey, nil
+	}
+	if edKey, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return edKey, nil
+	}
+
+	return nil, errors.New("the PEM key is not a RSA, ECDSA or Ed25519 key")
+}
+
+// parse the DER RSA, ECDSA or Ed25519 key
+func parseDERKey(data []byte, private bool) (any, error) {
+	var signer crypto.Signer
+	if key, err := x509.ParsePKCS8PrivateKey(data); err == nil {
+		signer, _ = key.(crypto.Signer)
+	} else if key, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		signer = key
+	} else if key, err := x509.ParseECPrivateKey(data); err == nil {
+		signer = key
+	}
+
+	if signer != nil {
+		return checkKeyType(publicIfNeeded(signer, private))
+	}
+
+	if private {
+		return nil, errors.New("the DER key is not a private key")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(data); err == nil {
+		return checkKeyType(key)
+	}
+	if key, err := x509.ParsePKCS1PublicKey(data); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("the DER key is not a RSA, ECDSA or Ed25519 key")
+}
+
+// check the key is a RSA, ECDSA or Ed25519 key
+func checkKeyType(key any) (any, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey,
+		*ecdsa.PrivateKey, *ecdsa.PublicKey,
+		ed25519.PrivateKey, ed25519.PublicKey:
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("the %s key is not a RSA, ECDSA or Ed25519 key", keyTypeName(key))
+}
+
+// return the public key when private is false
+func publicIfNeeded(key crypto.Signer, private bool) any {
+	if private {
+		return key
+	}
+
+	return key.Public()
+}
+
+// check the alg is registered
+func checkAlg(alg string) error {
+	if jwt.GetSigningMethod(alg) != nil {
+		return nil
+	}
+
+	algs := jwt.GetSigningMethodAlgs()
+	slices.Sort(algs)
+
+	return fmt.Errorf("unknown alg %q, the algs are %s", alg, strings.Join(algs, ", "))
+}
+
+// return the error when the key can not be used with the alg
+func keyTypeError(key any, alg string) error {
+	return fmt.Errorf("the %s key can not be used with the %s alg", keyTypeName(key), alg)
+}
+
+// return the key type name
+func keyTypeName(key any) string {
+	switch key.(type) {
+	case []byte:
+		return "HMAC"
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return "RSA"
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey:
+		return "ECDSA"
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return "Ed25519"
+	}
+
+	return fmt.Sprintf("%T", key)
+}
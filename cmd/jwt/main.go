@@ -0,0 +1,112 @@
+// Command jwt decodes, signs and verifies JSON Web Tokens, and generates keys.
+// The tokens and keys are only used on the local machine.
+//
+// Usage:
+//
+//	jwt decode [token]
+//	jwt sign -alg ES256 -key private.pem [-hmac] [-claims claims.json] [-exp 1h]
+//	jwt verify -key public.pem [-hmac] [-alg ES256] [token]
+//	jwt keygen -alg ES256 [-jwk] [-kid id]
+//
+// The token and the claims are read from stdin when they are not set.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// the clock of the time claims, replaced in tests
+var clock jwt.Clock = jwt.SystemClock
+
+const usage = `Usage: jwt <command> [flags]
+
+Commands:
+  decode   decode a token without verifying the signature
+  sign     sign the JSON claims
+  verify   verify a token and print the claims
+  keygen   generate a signing key
+
+Run "jwt <command> -h" for the command flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run the command and return the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var cmd func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+	switch args[0] {
+	case "decode":
+		cmd = runDecode
+	case "sign":
+		cmd = runSign
+	case "verify":
+		cmd = runVerify
+	case "keygen":
+		cmd = runKeygen
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "jwt: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err := cmd(args[1:], stdin, stdout, stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		fmt.Fprintf(stderr, "jwt %s: %s\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+// return a new flag set which writes to stderr
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("jwt "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	return fs
+}
+
+// read the token from the args or stdin, the `Bearer ` prefix is removed
+func readToken(args []string, stdin io.Reader) (string, error) {
+	var token string
+	if len(args) > 0 {
+		token = args[0]
+	} else {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", err
+		}
+
+		token = string(data)
+	}
+
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+
+	if token == "" {
+		return "", errors.New("token is empty")
+	}
+
+	return token, nil
+}
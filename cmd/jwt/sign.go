@@ -0,0 +1,139 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// header flag values, `name=value`
+type headerFlags map[string]any
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]any(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("header %q is not name=value", value)
+	}
+
+	h[name] = v
+	return nil
+}
+
+// sign the JSON claims
+func runSign(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	headers := headerFlags{}
+
+	fs := newFlagSet("sign", stderr)
+	alg := fs.String("alg", "", "the signing `alg`, like HS256, RS256, ES256 or EdDSA")
+	keyPath := fs.String("key", "", "the private key `file`, a PEM, DER or JWK key")
+	hmac := fs.Bool("hmac", false, "the key file is the raw HMAC secret")
+	claimsPath := fs.String("claims", "-", "the JSON claims `file`, - is stdin")
+	kid := fs.String("kid", "", "the `kid` header")
+	exp := fs.Duration("exp", 0, "set the `exp` claim with the `duration` from now")
+	iat := fs.Bool("iat", false, "set the `iat` claim with now")
+	fs.Var(headers, "header", "add a `name=value` header, can be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := checkAlg(*alg); err != nil {
+		return err
+	}
+
+	key, err := loadKey(*keyPath, true, *hmac)
+	if err != nil {
+		return err
+	}
+
+	claims, err := readClaims(*claimsPath, stdin)
+	if err != nil {
+		return err
+	}
+
+	t := clock.Now()
+	if *iat {
+		claims["iat"] = t.Unix()
+	}
+	if *exp != 0 {
+		claims["exp"] = t.Add(*exp).Unix()
+	}
+	if *kid != "" {
+		headers["kid"] = *kid
+	}
+
+	var tokenString string
+	switch k := key.(type) {
+	case []byte:
+		tokenString, err = signWith(*alg, k, headers, claims)
+	case *rsa.PrivateKey:
+		tokenString, err = signWith(*alg, k, headers, claims)
+	case *ecdsa.PrivateKey:
+		tokenString, err = signWith(*alg, k, headers, claims)
+	case ed25519.PrivateKey:
+		tokenString, err = signWith(*alg, k, headers, claims)
+	default:
+		err = keyTypeError(key, *alg)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, tokenString)
+	return err
+}
+
+// read the JSON claims from the file or stdin
+func readClaims(path string, stdin io.Reader) (map[string]any, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("claims are not a JSON object: %w", err)
+	}
+	if claims == nil {
+		claims = map[string]any{}
+	}
+
+	return claims, nil
+}
+
+// sign the claims with the registered signing method of the alg
+func signWith[S any](alg string, key S, headers, claims map[string]any) (string, error) {
+	signer, ok := jwt.GetSigningMethod(alg).(jwt.ISigning[S])
+	if !ok {
+		return "", keyTypeError(key, alg)
+	}
+
+	b := jwt.NewBuilder(signer, jwt.JWTEncoder).
+		WithClaims(jwt.MapClaims(claims))
+	for name, value := range headers {
+		b.WithHeader(name, value)
+	}
+
+	t, err := b.GetToken(key)
+	if err != nil {
+		return "", err
+	}
+
+	return t.SignedString()
+}
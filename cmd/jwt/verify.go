@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// verify the token and print the claims
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	alg := fs.String("alg", "", "the expected `alg`, the algs of the key type are accepted when empty")
+	keyPath := fs.String("key", "", "the key `file`, a PEM, DER or JWK key")
+	hmac := fs.Bool("hmac", false, "the key file is the raw HMAC secret")
+	issuer := fs.String("iss", "", "the expected `iss` claim")
+	leeway := fs.Duration("leeway", 0, "the `leeway` for the exp, nbf and iat claims")
+	skipClaims := fs.Bool("skip-claims", false, "only verify the signature, skip the claims validation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *alg == jwt.SigningNone.Alg() {
+		return errors.New("the unsecured none alg can not be verified")
+	}
+	if *alg != "" {
+		if err := checkAlg(*alg); err != nil {
+			return err
+		}
+	}
+
+	key, err := loadKey(*keyPath, false, *hmac)
+	if err != nil {
+		return err
+	}
+
+	tokenString, err := readToken(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	opt := jwt.ParserOption{
+		Encoder:              jwt.JWTEncoder,
+		Issuer:               *issuer,
+		Leeway:               *leeway,
+		Clock:                clock,
+		SkipClaimsValidation: *skipClaims,
+	}
+	if *alg != "" {
+		opt.ValidMethods = []string{*alg}
+	}
+
+	var t *jwt.Token
+	switch k := key.(type) {
+	case []byte:
+		t, err = verifyWith(tokenString, k, opt)
+	case *rsa.PublicKey:
+		t, err = verifyWith(tokenString, k, opt)
+	case *ecdsa.PublicKey:
+		t, err = verifyWith(tokenString, k, opt)
+	case ed25519.PublicKey:
+		t, err = verifyWith(tokenString, k, opt)
+	default:
+		err = fmt.Errorf("the %s key can not verify a token", keyTypeName(key))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "The token is valid.")
+
+	fmt.Fprintln(stdout, "\nHeader:")
+	if err := writeJSONRaw(stdout, t.GetHeaderRaw()); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "\nClaims:")
+	if err := writeJSONRaw(stdout, t.GetClaimsRaw()); err != nil {
+		return err
+	}
+
+	claims, err := t.GetClaims()
+	if err != nil {
+		return err
+	}
+	writeTimes(stdout, claims)
+
+	return nil
+}
+
+// verify the token with the key, the algs of the key type
+// are accepted when the option has no ValidMethods
+func verifyWith[V any](tokenString string, key V, opt jwt.ParserOption) (*jwt.Token, error) {
+	if opt.ValidMethods == nil {
+		opt.ValidMethods = keyAlgs[V]()
+	}
+
+	return jwt.Parse(tokenString, func(t *jwt.Token) (V, error) {
+		return key, nil
+	}, opt)
+}
+
+// return the registered algs which verify with the V key,
+// the unsecured none alg is not returned
+func keyAlgs[V any]() []string {
+	algs := []string{}
+	for _, alg := range jwt.GetSigningMethodAlgs() {
+		if alg == jwt.SigningNone.Alg() {
+			continue
+		}
+
+		if _, ok := jwt.GetSigningMethod(alg).(jwt.IVerifying[V]); ok {
+			algs = append(algs, alg)
+		}
+	}
+
+	slices.Sort(algs)
+
+	return algs
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+var (
+	ErrInsufficientScope = errors.New("go-jwt: token has insufficient scope")
+	ErrInsufficientRole  = errors.New("go-jwt: token has insufficient role")
+)
+
+const (
+	// default scope claim, see RFC 8693 section 4.2
+	DefaultScopeClaim = "scope"
+
+	// default role claim
+	DefaultRoleClaim = "roles"
+)
+
+// RequireScopes returns a wrapper which requires the token has all the
+// scopes, the scope claim is a space-delimited string or a string array.
+// A malformed scope claim is an insufficient scope too.
+// It is used after the Handler, which stores the token claims.
+func (m *Middleware[V]) RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return m.authorize(func(claims jwt.MapClaims) error {
+		values, err := claimValues(claims, m.scopeClaim)
+		if err != nil {
+			return insufficientError(ErrInsufficientScope, scopes, err)
+		}
+
+		for _, scope := range scopes {
+			if !slices.Contains(values, scope) {
+				return insufficientError(ErrInsufficientScope, scopes, nil)
+			}
+		}
+
+		return nil
+	})
+}
+
+// RequireAnyRole returns a wrapper which requires the token has one of
+// the roles, the role claim is a space-delimited string or a string array.
+// A malformed role claim is an insufficient role too.
+// It is used after the Handler, which stores the token claims.
+func (m *Middleware[V]) RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+	return m.authorize(func(claims jwt.MapClaims) error {
+		values, err := claimValues(claims, m.roleClaim)
+		if err != nil {
+			return insufficientError(ErrInsufficientRole, nil, err)
+		}
+
+		if slices.ContainsFunc(roles, func(role string) bool {
+			return slices.Contains(values, role)
+		}) {
+			return nil
+		}
+
+		return insufficientError(ErrInsufficientRole, nil, nil)
+	})
+}
+
+// return the forbidden error, the claim error is wrapped
+// but not written to the description
+func insufficientError(err error, scopes []string, claimErr error) *Error {
+	e := &Error{
+		Status:      http.StatusForbidden,
+		Code:        ErrorCodeInsufficientScope,
+		Description: err.Error(),
+		Scope:       scopes,
+		Err:         err,
+	}
+
+	if claimErr != nil {
+		e.Err = jwt.NewError("", err, claimErr)
+	}
+
+	return e
+}
+
+// return a wrapper which checks the context claims
+func (m *Middleware[V]) authorize(check func(claims jwt.MapClaims) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				m.errorHandler(w, r, ErrTokenNotFound)
+				return
+			}
+
+			if err := check(claims); err != nil {
+				m.errorHandler(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// return the claim values, the single string is space-delimited
+func claimValues(claims jwt.MapClaims, name string) ([]string, error) {
+	values, err := claims.GetClaimsString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if values.AsString && len(values.Value) == 1 {
+		return strings.Fields(values.Value[0]), nil
+	}
+
+	return values.Value, nil
+}
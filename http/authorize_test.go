@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_RequireScopes(t *testing.T) {
+	m := New(testKeyFunc, WithRealm("example"))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	scopes := m.Handler(m.RequireScopes("read", "write")(ok))
+	roles := m.Handler(m.RequireAnyRole("admin", "editor")(ok))
+
+	tests := []struct {
+		name      string
+		handler   http.Handler
+		claims    map[string]any
+		status    int
+		challenge string
+	}{
+		{
+			name:    "scope string",
+			handler: scopes,
+			claims:  map[string]any{"scope": "read write delete"},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "scope array",
+			handler: scopes,
+			claims:  map[string]any{"scope": []string{"read", "write"}},
+			status:  http.StatusOK,
+		},
+		{
+			name:      "insufficient scope",
+			handler:   scopes,
+			claims:    map[string]any{"scope": "read"},
+			status:    http.StatusForbidden,
+			challenge: `Bearer realm="example", error="insufficient_scope", error_description="go-jwt: token has insufficient scope", scope="read write"`,
+		},
+		{
+			name:      "malformed scope",
+			handler:   scopes,
+			claims:    map[string]any{"scope": []any{"read", "write", 1}},
+			status:    http.StatusForbidden,
+			challenge: `Bearer realm="example", error="insufficient_scope", error_description="go-jwt: token has insufficient scope", scope="read write"`,
+		},
+		{
+			name:    "any role",
+			handler: roles,
+			claims:  map[string]any{"roles": []string{"user", "editor"}},
+			status:  http.StatusOK,
+		},
+		{
+			name:      "insufficient role",
+			handler:   roles,
+			claims:    map[string]any{"roles": "user"},
+			status:    http.StatusForbidden,
+			challenge: `Bearer realm="example", error="insufficient_scope", error_description="go-jwt: token has insufficient role"`,
+		},
+		{
+			name:      "malformed role",
+			handler:   roles,
+			claims:    map[string]any{"roles": []any{"admin", 1}},
+			status:    http.StatusForbidden,
+			challenge: `Bearer realm="example", error="insufficient_scope", error_description="go-jwt: token has insufficient role"`,
+		},
+		{
+			name:      "no role",
+			handler:   roles,
+			claims:    map[string]any{"sub": "user"},
+			status:    http.StatusForbidden,
+			challenge: `Bearer realm="example", error="insufficient_scope", error_description="go-jwt: token has insufficient role"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", "Bearer "+newTestToken(t, tt.claims))
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("status got %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("WWW-Authenticate got %s, want %s", got, tt.challenge)
+			}
+		})
+	}
+
+	// without the Handler, the request has no claims
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	m.RequireScopes("read")(ok).ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status got %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func Test_RequireScopes_Claim(t *testing.T) {
+	m := New(testKeyFunc, WithScopeClaim("scp"), WithRoleClaim("groups"))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	handler := m.Handler(m.RequireScopes("read")(m.RequireAnyRole("admin")(ok)))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+newTestToken(t, map[string]any{
+		"scp":    []string{"read"},
+		"groups": []string{"admin"},
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status got %d, want %d", w.Code, http.StatusOK)
+	}
+}
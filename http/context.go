@@ -0,0 +1,44 @@
+package http
+
+import (
+	"context"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// the context key of the verified token
+type contextKey struct{}
+
+// the verified token and its claims
+type contextValue struct {
+	token  *jwt.Token
+	claims jwt.MapClaims
+}
+
+// NewContext returns a context with the verified token and its claims.
+func NewContext(ctx context.Context, token *jwt.Token, claims jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, contextKey{}, contextValue{
+		token:  token,
+		claims: claims,
+	})
+}
+
+// FromContext returns the verified token stored by the middleware.
+func FromContext(ctx context.Context) (*jwt.Token, bool) {
+	v, ok := ctx.Value(contextKey{}).(contextValue)
+	if !ok {
+		return nil, false
+	}
+
+	return v.token, true
+}
+
+// ClaimsFromContext returns the verified token claims stored by the middleware.
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	v, ok := ctx.Value(contextKey{}).(contextValue)
+	if !ok {
+		return nil, false
+	}
+
+	return v.claims, true
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+// the error codes, see RFC 6750 section 3.1
+const (
+	ErrorCodeInvalidRequest    = "invalid_request"
+	ErrorCodeInvalidToken      = "invalid_token"
+	ErrorCodeInsufficientScope = "insufficient_scope"
+)
+
+// the error descriptions of the ValidationError categories, in order.
+// The error causes, like the key lookup errors, are not written to the
+// clients, they can have the key ids, the urls and the parse errors.
+var categoryDescriptions = []struct {
+	category    error
+	description string
+}{
+	{jwt.ErrJWTTokenExpired, "the token is expired"},
+	{jwt.ErrJWTTokenNotValidYet, "the token is not valid yet"},
+	{jwt.ErrJWTTokenRevoked, "the token is revoked"},
+	{jwt.ErrJWTTokenReplayed, "the token has been used"},
+	{jwt.ErrJWTTokenSignatureInvalid, "the token signature is invalid"},
+	{jwt.ErrJWTTokenMalformed, "the token is malformed"},
+	{jwt.ErrJWTTokenInvalidClaims, "the token claims are invalid"},
+	{jwt.ErrJWTTokenUnverifiable, "the token can not be verified"},
+}
+
+// the description of the errors without a known category
+const invalidTokenDescription = "the token is invalid"
+
+// Error is a bearer token error rendered with the
+// WWW-Authenticate header, see RFC 6750 section 3.
+type Error struct {
+	// the response status code
+	Status int
+
+	// the error code, empty when the request has no token
+	Code string
+
+	// the error description
+	Description string
+
+	// the scopes required to access the resource
+	Scope []string
+
+	// the cause error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	if e.Description != "" {
+		return e.Description
+	}
+
+	return http.StatusText(e.Status)
+}
+
+// Unwrap returns the cause error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// return the bearer token error of the error
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		return &Error{
+			Status: http.StatusUnauthorized,
+			Err:    err,
+		}
+	case errors.Is(err, ErrAuthorizationHeader):
+		return &Error{
+			Status:      http.StatusBadRequest,
+			Code:        ErrorCodeInvalidRequest,
+			Description: err.Error(),
+			Err:         err,
+		}
+	}
+
+	return &Error{
+		Status:      http.StatusUnauthorized,
+		Code:        ErrorCodeInvalidToken,
+		Description: tokenErrorDescription(err),
+		Err:         err,
+	}
+}
+
+// return the fixed description of the token error
+func tokenErrorDescription(err error) string {
+	if errors.Is(err, ErrUnsecuredToken) {
+		return "the unsecured token is not allowed"
+	}
+
+	var ve *jwt.ValidationError
+	if errors.As(err, &ve) {
+		for _, d := range categoryDescriptions {
+			if ve.Has(d.category) {
+				return d.description
+			}
+		}
+	}
+
+	return invalidTokenDescription
+}
+
+// WriteError writes the error response with the WWW-Authenticate header.
+func WriteError(w http.ResponseWriter, realm string, err error) {
+	e := toError(err)
+
+	w.Header().Set("WWW-Authenticate", e.challenge(realm))
+	http.Error(w, http.StatusText(e.Status), e.Status)
+}
+
+// return the WWW-Authenticate header value
+func (e *Error) challenge(realm string) string {
+	var params []string
+	if realm != "" {
+		params = append(params, authParam("realm", realm))
+	}
+	if e.Code != "" {
+		params = append(params, authParam("error", e.Code))
+	}
+	if e.Description != "" {
+		params = append(params, authParam("error_description", e.Description))
+	}
+	if len(e.Scope) > 0 {
+		params = append(params, authParam("scope", strings.Join(e.Scope, " ")))
+	}
+
+	if len(params) == 0 {
+		return "Bearer"
+	}
+
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// return the auth param, the value chars not allowed
+// by RFC 6750 section 3 are removed
+func authParam(name, value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return -1
+		}
+
+		return r
+	}, value)
+
+	return fmt.Sprintf(`%s="%s"`, name, value)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrTokenNotFound       = errors.New("go-jwt: token not found in request")
+	ErrAuthorizationHeader = errors.New("go-jwt: authorization header invalid")
+)
+
+// Extractor extracts the token string from the request
+type Extractor interface {
+	// Extract returns the token string, or ErrTokenNotFound when
+	// the request has no token.
+	Extract(r *http.Request) (string, error)
+}
+
+// ExtractorFunc is a function Extractor
+type ExtractorFunc func(r *http.Request) (string, error)
+
+// Extract implements the Extractor interface.
+func (f ExtractorFunc) Extract(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// MultiExtractor tries the extractors in order, and returns the
+// first token found. The errors other than ErrTokenNotFound are
+// returned at once.
+type MultiExtractor []Extractor
+
+// Extract implements the Extractor interface.
+func (e MultiExtractor) Extract(r *http.Request) (string, error) {
+	for _, extractor := range e {
+		token, err := extractor.Extract(r)
+		if errors.Is(err, ErrTokenNotFound) {
+			continue
+		}
+
+		return token, err
+	}
+
+	return "", ErrTokenNotFound
+}
+
+// BearerExtractor extracts the token from the `Authorization: Bearer`
+// header, see RFC 6750 section 2.1.
+func BearerExtractor() Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			return "", ErrTokenNotFound
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return "", ErrAuthorizationHeader
+		}
+
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return "", ErrAuthorizationHeader
+		}
+
+		return token, nil
+	})
+}
+
+// HeaderExtractor extracts the token from the header value.
+func HeaderExtractor(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		return found(r.Header.Get(name))
+	})
+}
+
+// CookieExtractor extracts the token from the cookie.
+func CookieExtractor(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return "", ErrTokenNotFound
+		}
+
+		return found(cookie.Value)
+	})
+}
+
+// FormExtractor extracts the token from the form-encoded body
+// parameter, see RFC 6750 section 2.2.
+func FormExtractor(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		return found(r.PostFormValue(name))
+	})
+}
+
+// QueryExtractor extracts the token from the URL query
+// parameter, see RFC 6750 section 2.3.
+func QueryExtractor(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		return found(r.URL.Query().Get(name))
+	})
+}
+
+// return ErrTokenNotFound when the token is empty
+func found(token string) (string, error) {
+	if token == "" {
+		return "", ErrTokenNotFound
+	}
+
+	return token, nil
+}
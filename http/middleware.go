@@ -0,0 +1,131 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+var (
+	ErrUnsecuredToken = errors.New("go-jwt: unsecured token not allowed")
+)
+
+type config struct {
+	extractor           Extractor
+	parserOption        jwt.ParserOption
+	realm               string
+	errorHandler        func(w http.ResponseWriter, r *http.Request, err error)
+	credentialsOptional bool
+	scopeClaim          string
+	roleClaim           string
+}
+
+// Middleware verifies the bearer token of the requests, and stores
+// the verified token and its claims in the request context.
+type Middleware[V any] struct {
+	keyFunc func(t *jwt.Token) (V, error)
+
+	config
+}
+
+// New returns a new Middleware, the token is verified with the keyFunc key.
+// The token is extracted from the `Authorization: Bearer` header by default.
+// The unsecured `none` tokens are rejected unless the parser option
+// ValidMethods has `none`.
+func New[V any](keyFunc func(t *jwt.Token) (V, error), options ...Option) *Middleware[V] {
+	m := &Middleware[V]{
+		keyFunc: keyFunc,
+		config: config{
+			extractor:  BearerExtractor(),
+			scopeClaim: DefaultScopeClaim,
+			roleClaim:  DefaultRoleClaim,
+		},
+	}
+
+	// Loop through our middleware options and apply them
+	for _, option := range options {
+		option(&m.config)
+	}
+
+	if m.parserOption.Encoder == nil {
+		m.parserOption.Encoder = jwt.JWTEncoder
+	}
+	if !slices.Contains(m.parserOption.ValidMethods, jwt.SigningNone.Alg()) {
+		m.keyFunc = rejectNone(keyFunc)
+	}
+	if m.errorHandler == nil {
+		m.errorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			WriteError(w, m.realm, err)
+		}
+	}
+
+	return m
+}
+
+// Handler returns the handler which verifies the token before next.
+func (m *Middleware[V]) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, claims, err := m.Verify(r)
+		if err != nil {
+			if m.credentialsOptional && errors.Is(err, ErrTokenNotFound) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			m.errorHandler(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), token, claims)))
+	})
+}
+
+// HandlerFunc returns the handler which verifies the token before next.
+func (m *Middleware[V]) HandlerFunc(next http.HandlerFunc) http.Handler {
+	return m.Handler(next)
+}
+
+// Verify extracts and verifies the token of the request.
+func (m *Middleware[V]) Verify(r *http.Request) (*jwt.Token, jwt.MapClaims, error) {
+	tokenString, err := m.extractor.Extract(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := jwt.Parse(tokenString, m.keyFunc, m.parserOption)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims, err := token.GetClaims()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return token, claims, nil
+}
+
+// return the keyFunc which rejects the unsecured `none` tokens
+func rejectNone[V any](keyFunc func(t *jwt.Token) (V, error)) func(t *jwt.Token) (V, error) {
+	return func(t *jwt.Token) (V, error) {
+		var key V
+
+		header, err := t.GetHeader()
+		if err != nil {
+			return key, err
+		}
+
+		alg, err := header.GetAlgorithm()
+		if err != nil {
+			return key, err
+		}
+
+		if alg == jwt.SigningNone.Alg() {
+			return key, ErrUnsecuredToken
+		}
+
+		return keyFunc(t)
+	}
+}
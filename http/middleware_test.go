@@ -0,0 +1,347 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+var testKey = []byte("test-key")
+
+func testKeyFunc(t *jwt.Token) ([]byte, error) {
+	return testKey, nil
+}
+
+func newTestToken(t *testing.T, claims map[string]any) string {
+	tokenString, err := jwt.SigningMethodHS256.Sign(claims, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenString
+}
+
+// an unsigned token with forged claims
+func newNoneToken(t *testing.T) string {
+	tokenString, err := jwt.SigningMethodNone.Sign(map[string]any{
+		"sub":   "admin",
+		"scope": "admin",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenString
+}
+
+func testHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := FromContext(r.Context())
+		if !ok {
+			w.Write([]byte("anonymous"))
+			return
+		}
+
+		claims, ok := ClaimsFromContext(r.Context())
+		if !ok {
+			t.Error("ClaimsFromContext not found")
+		}
+
+		if _, err := token.GetClaims(); err != nil {
+			t.Error(err)
+		}
+
+		sub, _ := claims.GetSubject()
+		w.Write([]byte(sub))
+	})
+}
+
+func Test_Middleware(t *testing.T) {
+	m := New(testKeyFunc, WithRealm("example"))
+	handler := m.Handler(testHandler(t))
+
+	tokenString := newTestToken(t, map[string]any{
+		"sub": "user",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tokenString)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status got %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != "user" {
+		t.Errorf("body got %s, want %s", w.Body.String(), "user")
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		challenge     string
+	}{
+		{
+			name:          "no token",
+			authorization: "",
+			status:        http.StatusUnauthorized,
+			challenge:     `Bearer realm="example"`,
+		},
+		{
+			name:          "invalid scheme",
+			authorization: "Basic dXNlcjpwYXNz",
+			status:        http.StatusBadRequest,
+			challenge:     `Bearer realm="example", error="invalid_request", error_description="go-jwt: authorization header invalid"`,
+		},
+		{
+			name: "expired",
+			authorization: "Bearer " + newTestToken(t, map[string]any{
+				"sub": "user",
+				"exp": time.Now().Add(-time.Hour).Unix(),
+			}),
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer realm="example", error="invalid_token", error_description="the token is expired"`,
+		},
+		{
+			name:          "unsecured",
+			authorization: "Bearer " + newNoneToken(t),
+			status:        http.StatusUnauthorized,
+			challenge:     `Bearer realm="example", error="invalid_token", error_description="the unsecured token is not allowed"`,
+		},
+		{
+			name:          "malformed",
+			authorization: "Bearer abc",
+			status:        http.StatusUnauthorized,
+			challenge:     `Bearer realm="example", error="invalid_token", error_description="the token is malformed"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("status got %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("WWW-Authenticate got %s, want %s", got, tt.challenge)
+			}
+		})
+	}
+}
+
+func Test_Middleware_Options(t *testing.T) {
+	var handlerErr error
+
+	m := New(testKeyFunc,
+		WithExtractor(
+			BearerExtractor(),
+			CookieExtractor("token"),
+			QueryExtractor("access_token"),
+		),
+		WithParserOption(jwt.ParserOption{
+			Issuer: "issuer",
+		}),
+		WithCredentialsOptional(true),
+		WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			handlerErr = err
+			w.WriteHeader(http.StatusTeapot)
+		}),
+	)
+	handler := m.Handler(testHandler(t))
+
+	tokenString := newTestToken(t, map[string]any{
+		"sub": "user",
+		"iss": "issuer",
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/?access_token="+tokenString, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Body.String() != "user" {
+		t.Errorf("body got %s, want %s", w.Body.String(), "user")
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "token", Value: tokenString})
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Body.String() != "user" {
+		t.Errorf("body got %s, want %s", w.Body.String(), "user")
+	}
+
+	// no token is passed with the optional credentials
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Body.String() != "anonymous" {
+		t.Errorf("body got %s, want %s", w.Body.String(), "anonymous")
+	}
+
+	// the parser option is used
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+newTestToken(t, map[string]any{
+		"sub": "user",
+		"iss": "other",
+	}))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("status got %d, want %d", w.Code, http.StatusTeapot)
+	}
+	if !errors.Is(handlerErr, jwt.ErrJWTTokenInvalidIssuer) {
+		t.Errorf("error got %v, want %v", handlerErr, jwt.ErrJWTTokenInvalidIssuer)
+	}
+}
+
+func Test_Middleware_None(t *testing.T) {
+	tokenString := newNoneToken(t)
+
+	// the `none` tokens are only accepted when they are in the ValidMethods
+	m := New(func(t *jwt.Token) ([]byte, error) {
+		return nil, nil
+	}, WithParserOption(jwt.ParserOption{
+		ValidMethods: []string{"none"},
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tokenString)
+
+	if _, _, err := m.Verify(r); err != nil {
+		t.Fatal(err)
+	}
+
+	m = New(func(t *jwt.Token) ([]byte, error) {
+		return nil, nil
+	}, WithParserOption(jwt.ParserOption{
+		ValidMethods: []string{"HS256"},
+	}))
+	if _, _, err := m.Verify(r); !errors.Is(err, jwt.ErrJWTTokenUnverifiable) {
+		t.Errorf("Verify got %v, want %v", err, jwt.ErrJWTTokenUnverifiable)
+	}
+
+	m = New(testKeyFunc)
+	if _, _, err := m.Verify(r); !errors.Is(err, ErrUnsecuredToken) {
+		t.Errorf("Verify got %v, want %v", err, ErrUnsecuredToken)
+	}
+}
+
+func Test_Middleware_ErrorDescription(t *testing.T) {
+	keyErr := errors.New("key kid-1 not found at https://internal.example.com/jwks")
+
+	m := New(func(t *jwt.Token) ([]byte, error) {
+		return nil, keyErr
+	}, WithRealm("example"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+newTestToken(t, map[string]any{"sub": "user"}))
+
+	w := httptest.NewRecorder()
+	m.Handler(testHandler(t)).ServeHTTP(w, r)
+
+	// the key lookup error is not written to the client
+	want := `Bearer realm="example", error="invalid_token", error_description="the token can not be verified"`
+	if got := w.Header().Get("WWW-Authenticate"); got != want {
+		t.Errorf("WWW-Authenticate got %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{keyErr, "the token is invalid"},
+		{jwt.ErrJWTTokenExpired, "the token is invalid"},
+	}
+
+	for _, tt := range tests {
+		if got := toError(tt.err).Description; got != tt.want {
+			t.Errorf("Description got %s, want %s", got, tt.want)
+		}
+	}
+
+	_, err := jwt.Parse(newTestToken(t, map[string]any{"sub": "user"}), func(t *jwt.Token) ([]byte, error) {
+		return []byte("other-key"), nil
+	})
+	if got := toError(err).Description; got != "the token signature is invalid" {
+		t.Errorf("Description got %s, want %s", got, "the token signature is invalid")
+	}
+
+	store := jwt.NewMemoryRevocationStore()
+	store.RevokeSubject("user", time.Now().Add(time.Hour))
+
+	_, err = jwt.Parse(newTestToken(t, map[string]any{"sub": "user"}), testKeyFunc, jwt.ParserOption{
+		Encoder:    jwt.JWTEncoder,
+		Revocation: store,
+	})
+	if got := toError(err).Description; got != "the token is revoked" {
+		t.Errorf("Description got %s, want %s", got, "the token is revoked")
+	}
+}
+
+func Test_Extractors(t *testing.T) {
+	form := url.Values{"access_token": {"form-token"}}
+
+	r := httptest.NewRequest(http.MethodPost, "/?token=query-token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Token", "header-token")
+
+	extractors := []struct {
+		extractor Extractor
+		want      string
+	}{
+		{FormExtractor("access_token"), "form-token"},
+		{QueryExtractor("token"), "query-token"},
+		{HeaderExtractor("X-Token"), "header-token"},
+		{MultiExtractor{BearerExtractor(), CookieExtractor("token"), QueryExtractor("token")}, "query-token"},
+	}
+
+	for _, e := range extractors {
+		got, err := e.extractor.Extract(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != e.want {
+			t.Errorf("Extract got %s, want %s", got, e.want)
+		}
+	}
+
+	_, err := MultiExtractor{BearerExtractor(), CookieExtractor("token")}.Extract(r)
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Extract got %v, want %v", err, ErrTokenNotFound)
+	}
+
+	r.Header.Set("Authorization", "bearer  abc ")
+
+	got, err := BearerExtractor().Extract(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "abc" {
+		t.Errorf("Extract got %s, want %s", got, "abc")
+	}
+
+	r.Header.Set("Authorization", "Bearer")
+	if _, err := BearerExtractor().Extract(r); !errors.Is(err, ErrAuthorizationHeader) {
+		t.Errorf("Extract got %v, want %v", err, ErrAuthorizationHeader)
+	}
+}
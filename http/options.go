@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+type Option func(*config)
+
+// WithExtractor sets the extractors, they are tried in order.
+func WithExtractor(extractors ...Extractor) Option {
+	return func(c *config) {
+		c.extractor = MultiExtractor(extractors)
+	}
+}
+
+// WithParserOption sets the parser option used to validate the token.
+func WithParserOption(opt jwt.ParserOption) Option {
+	return func(c *config) {
+		c.parserOption = opt
+	}
+}
+
+// WithRealm sets the realm of the WWW-Authenticate header.
+func WithRealm(realm string) Option {
+	return func(c *config) {
+		c.realm = realm
+	}
+}
+
+// WithErrorHandler sets the handler which writes the error response.
+func WithErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(c *config) {
+		c.errorHandler = handler
+	}
+}
+
+// WithCredentialsOptional sets the requests without token are passed
+// to the next handler, the invalid tokens are still rejected.
+func WithCredentialsOptional(optional bool) Option {
+	return func(c *config) {
+		c.credentialsOptional = optional
+	}
+}
+
+// WithScopeClaim sets the claim name used by RequireScopes.
+func WithScopeClaim(name string) Option {
+	return func(c *config) {
+		c.scopeClaim = name
+	}
+}
+
+// WithRoleClaim sets the claim name used by RequireAnyRole.
+func WithRoleClaim(name string) Option {
+	return func(c *config) {
+		c.roleClaim = name
+	}
+}
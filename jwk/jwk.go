@@ -0,0 +1,579 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrKeyTypeUnsupported = errors.New("go-jwt: jwk key type unsupported")
+	ErrCurveUnsupported   = errors.New("go-jwt: jwk curve unsupported")
+	ErrKeyInvalid         = errors.New("go-jwt: jwk key invalid")
+	ErrKeyMemberInvalid   = errors.New("go-jwt: jwk key member invalid")
+	ErrNotPrivateKey      = errors.New("go-jwt: jwk key is not a private key")
+	ErrNotRSAPrivateKey   = errors.New("go-jwt: jwk key is not a valid RSA private key")
+	ErrNotRSAPublicKey    = errors.New("go-jwt: jwk key is not a valid RSA public key")
+	ErrNotECPrivateKey    = errors.New("go-jwt: jwk key is not a valid ECDSA private key")
+	ErrNotECPublicKey     = errors.New("go-jwt: jwk key is not a valid ECDSA public key")
+	ErrNotEdPrivateKey    = errors.New("go-jwt: jwk key is not a valid Ed25519 private key")
+	ErrNotEdPublicKey     = errors.New("go-jwt: jwk key is not a valid Ed25519 public key")
+	ErrNotHmacKey         = errors.New("go-jwt: jwk key is not a valid oct key")
+)
+
+// Key types, see RFC 7518 section 6.1
+const (
+	KeyTypeRSA = "RSA"
+	KeyTypeEC  = "EC"
+	KeyTypeOKP = "OKP"
+	KeyTypeOct = "oct"
+)
+
+// Curve names, see RFC 7518 section 6.2.1.1 and RFC 8037 section 2
+const (
+	CurveP256    = "P-256"
+	CurveP384    = "P-384"
+	CurveP521    = "P-521"
+	CurveEd25519 = "Ed25519"
+)
+
+// JWK represents a JSON Web Key, see RFC 7517.
+// The Key is one of *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey,
+// *ecdsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey or []byte.
+type JWK struct {
+	Key       any
+	KeyID     string
+	Algorithm string
+	Use       string
+	KeyOps    []string
+}
+
+// jwk json members
+type rawJWK struct {
+	Kty    string   `json:"kty"`
+	Kid    string   `json:"kid,omitempty"`
+	Alg    string   `json:"alg,omitempty"`
+	Use    string   `json:"use,omitempty"`
+	KeyOps []string `json:"key_ops,omitempty"`
+
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	D  string `json:"d,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	Dp string `json:"dp,omitempty"`
+	Dq string `json:"dq,omitempty"`
+	Qi string `json:"qi,omitempty"`
+
+	Oth []json.RawMessage `json:"oth,omitempty"`
+
+	K string `json:"k,omitempty"`
+}
+
+// New returns a JWK with the key. The key type must be supported.
+func New(key any) (*JWK, error) {
+	if _, err := keyType(key); err != nil {
+		return nil, err
+	}
+
+	return &JWK{
+		Key: key,
+	}, nil
+}
+
+// Parse parses a JSON encoded JWK.
+func Parse(data []byte) (*JWK, error) {
+	var k JWK
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// KeyType returns the `kty` of the key.
+func (k *JWK) KeyType() string {
+	kty, _ := keyType(k.Key)
+	return kty
+}
+
+// IsPrivate returns true when the key is a private or a symmetric key.
+func (k *JWK) IsPrivate() bool {
+	switch k.Key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, []byte:
+		return true
+	}
+
+	return false
+}
+
+// Public returns the JWK of the public key.
+func (k *JWK) Public() (*JWK, error) {
+	var pub any
+	switch key := k.Key.(type) {
+	case *rsa.PrivateKey:
+		pub = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		pub = &key.PublicKey
+	case ed25519.PrivateKey:
+		pub = key.Public()
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		pub = key
+	default:
+		return nil, ErrKeyTypeUnsupported
+	}
+
+	return &JWK{
+		Key:       pub,
+		KeyID:     k.KeyID,
+		Algorithm: k.Algorithm,
+		Use:       k.Use,
+		KeyOps:    k.KeyOps,
+	}, nil
+}
+
+// return the RSA private key, used by SignRSA and SignRSAPSS
+func (k *JWK) GetRSAPrivateKey() (*rsa.PrivateKey, error) {
+	if key, ok := k.Key.(*rsa.PrivateKey); ok {
+		return key, nil
+	}
+
+	return nil, ErrNotRSAPrivateKey
+}
+
+// return the RSA public key, used by SignRSA and SignRSAPSS
+func (k *JWK) GetRSAPublicKey() (*rsa.PublicKey, error) {
+	switch key := k.Key.(type) {
+	case *rsa.PublicKey:
+		return key, nil
+	case *rsa.PrivateKey:
+		return &key.PublicKey, nil
+	}
+
+	return nil, ErrNotRSAPublicKey
+}
+
+// return the ECDSA private key, used by SignECDSA
+func (k *JWK) GetECPrivateKey() (*ecdsa.PrivateKey, error) {
+	if key, ok := k.Key.(*ecdsa.PrivateKey); ok {
+		return key, nil
+	}
+
+	return nil, ErrNotECPrivateKey
+}
+
+// return the ECDSA public key, used by SignECDSA
+func (k *JWK) GetECPublicKey() (*ecdsa.PublicKey, error) {
+	switch key := k.Key.(type) {
+	case *ecdsa.PublicKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return &key.PublicKey, nil
+	}
+
+	return nil, ErrNotECPublicKey
+}
+
+// return the Ed25519 private key, used by SignEdDSA
+func (k *JWK) GetEdPrivateKey() (ed25519.PrivateKey, error) {
+	if key, ok := k.Key.(ed25519.PrivateKey); ok {
+		return key, nil
+	}
+
+	return nil, ErrNotEdPrivateKey
+}
+
+// return the Ed25519 public key, used by SignEdDSA
+func (k *JWK) GetEdPublicKey() (ed25519.PublicKey, error) {
+	switch key := k.Key.(type) {
+	case ed25519.PublicKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key.Public().(ed25519.PublicKey), nil
+	}
+
+	return nil, ErrNotEdPublicKey
+}
+
+// return the symmetric key, used by SignHmac
+func (k *JWK) GetHmacKey() ([]byte, error) {
+	if key, ok := k.Key.([]byte); ok {
+		return key, nil
+	}
+
+	return nil, ErrNotHmacKey
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (k JWK) MarshalJSON() ([]byte, error) {
+	raw, err := k.toRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (k *JWK) UnmarshalJSON(data []byte) error {
+	var raw rawJWK
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	key, err := raw.toKey()
+	if err != nil {
+		return err
+	}
+
+	k.Key = key
+	k.KeyID = raw.Kid
+	k.Algorithm = raw.Alg
+	k.Use = raw.Use
+	k.KeyOps = raw.KeyOps
+
+	return nil
+}
+
+func (k JWK) toRaw() (*rawJWK, error) {
+	raw := &rawJWK{
+		Kid:    k.KeyID,
+		Alg:    k.Algorithm,
+		Use:    k.Use,
+		KeyOps: k.KeyOps,
+	}
+
+	switch key := k.Key.(type) {
+	case *rsa.PublicKey:
+		raw.Kty = KeyTypeRSA
+		raw.N = encodeBigInt(key.N)
+		raw.E = encodeBigInt(big.NewInt(int64(key.E)))
+	case *rsa.PrivateKey:
+		if len(key.Primes) != 2 {
+			return nil, ErrNotRSAPrivateKey
+		}
+
+		dp, dq, qi := rsaCRTValues(key)
+		if qi == nil {
+			return nil, ErrNotRSAPrivateKey
+		}
+
+		raw.Kty = KeyTypeRSA
+		raw.N = encodeBigInt(key.N)
+		raw.E = encodeBigInt(big.NewInt(int64(key.E)))
+		raw.D = encodeBigInt(key.D)
+		raw.P = encodeBigInt(key.Primes[0])
+		raw.Q = encodeBigInt(key.Primes[1])
+		raw.Dp = encodeBigInt(dp)
+		raw.Dq = encodeBigInt(dq)
+		raw.Qi = encodeBigInt(qi)
+	case *ecdsa.PublicKey:
+		crv, size, err := curveName(key.Curve)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := key.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		raw.Kty = KeyTypeEC
+		raw.Crv = crv
+		raw.X = encodeBytes(data[1 : 1+size])
+		raw.Y = encodeBytes(data[1+size:])
+	case *ecdsa.PrivateKey:
+		pub, err := JWK{Key: &key.PublicKey}.toRaw()
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := key.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		raw.Kty = pub.Kty
+		raw.Crv = pub.Crv
+		raw.X = pub.X
+		raw.Y = pub.Y
+		raw.D = encodeBytes(d)
+	case ed25519.PublicKey:
+		raw.Kty = KeyTypeOKP
+		raw.Crv = CurveEd25519
+		raw.X = encodeBytes(key)
+	case ed25519.PrivateKey:
+		raw.Kty = KeyTypeOKP
+		raw.Crv = CurveEd25519
+		raw.X = encodeBytes(key.Public().(ed25519.PublicKey))
+		raw.D = encodeBytes(key.Seed())
+	case []byte:
+		raw.Kty = KeyTypeOct
+		raw.K = encodeBytes(key)
+	default:
+		return nil, ErrKeyTypeUnsupported
+	}
+
+	return raw, nil
+}
+
+func (raw *rawJWK) toKey() (any, error) {
+	switch raw.Kty {
+	case KeyTypeRSA:
+		return raw.toRSAKey()
+	case KeyTypeEC:
+		return raw.toECKey()
+	case KeyTypeOKP:
+		return raw.toOKPKey()
+	case KeyTypeOct:
+		k, err := decodeBytes(raw.K)
+		if err != nil || len(k) == 0 {
+			return nil, ErrKeyMemberInvalid
+		}
+
+		return k, nil
+	}
+
+	return nil, ErrKeyTypeUnsupported
+}
+
+func (raw *rawJWK) toRSAKey() (any, error) {
+	n, err := decodeBigInt(raw.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := decodeBigInt(raw.E)
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, ErrKeyMemberInvalid
+	}
+
+	pub := rsa.PublicKey{
+		N: n,
+		E: int(e.Int64()),
+	}
+
+	if raw.D == "" {
+		return &pub, nil
+	}
+
+	// multi-prime keys are not supported
+	if len(raw.Oth) > 0 {
+		return nil, ErrKeyTypeUnsupported
+	}
+
+	d, err := decodeBigInt(raw.D)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decodeBigInt(raw.P)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := decodeBigInt(raw.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	priv := &rsa.PrivateKey{
+		PublicKey: pub,
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+
+	if err := priv.Validate(); err != nil {
+		return nil, errors.Join(ErrKeyInvalid, err)
+	}
+
+	priv.Precompute()
+
+	return priv, nil
+}
+
+// return the CRT values of the RSA key, the key is not changed
+func rsaCRTValues(key *rsa.PrivateKey) (dp, dq, qi *big.Int) {
+	p, q := key.Primes[0], key.Primes[1]
+	one := big.NewInt(1)
+
+	dp = new(big.Int).Mod(key.D, new(big.Int).Sub(p, one))
+	dq = new(big.Int).Mod(key.D, new(big.Int).Sub(q, one))
+	qi = new(big.Int).ModInverse(q, p)
+
+	return dp, dq, qi
+}
+
+func (raw *rawJWK) toECKey() (any, error) {
+	curve, size, err := curveByName(raw.Crv)
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := decodeFixedBytes(raw.X, size)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := decodeFixedBytes(raw.Y, size)
+	if err != nil {
+		return nil, err
+	}
+
+	point := append([]byte{4}, x...)
+	point = append(point, y...)
+
+	pub, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+	if err != nil {
+		return nil, errors.Join(ErrKeyInvalid, err)
+	}
+
+	if raw.D == "" {
+		return pub, nil
+	}
+
+	d, err := decodeFixedBytes(raw.D, size)
+	if err != nil {
+		return nil, err
+	}
+
+	priv, err := ecdsa.ParseRawPrivateKey(curve, d)
+	if err != nil {
+		return nil, errors.Join(ErrKeyInvalid, err)
+	}
+
+	if !priv.PublicKey.Equal(pub) {
+		return nil, ErrKeyInvalid
+	}
+
+	return priv, nil
+}
+
+func (raw *rawJWK) toOKPKey() (any, error) {
+	if raw.Crv != CurveEd25519 {
+		return nil, ErrCurveUnsupported
+	}
+
+	x, err := decodeFixedBytes(raw.X, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	pub := ed25519.PublicKey(x)
+
+	if raw.D == "" {
+		return pub, nil
+	}
+
+	d, err := decodeFixedBytes(raw.D, ed25519.SeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	priv := ed25519.NewKeyFromSeed(d)
+	if !pub.Equal(priv.Public()) {
+		return nil, ErrKeyInvalid
+	}
+
+	return priv, nil
+}
+
+// return the `kty` of the key
+func keyType(key any) (string, error) {
+	switch key.(type) {
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		return KeyTypeRSA, nil
+	case *ecdsa.PublicKey, *ecdsa.PrivateKey:
+		return KeyTypeEC, nil
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return KeyTypeOKP, nil
+	case []byte:
+		return KeyTypeOct, nil
+	}
+
+	return "", ErrKeyTypeUnsupported
+}
+
+// return curve name and coordinate size
+func curveName(curve elliptic.Curve) (string, int, error) {
+	switch curve {
+	case elliptic.P256():
+		return CurveP256, 32, nil
+	case elliptic.P384():
+		return CurveP384, 48, nil
+	case elliptic.P521():
+		return CurveP521, 66, nil
+	}
+
+	return "", 0, ErrCurveUnsupported
+}
+
+// return curve and coordinate size
+func curveByName(name string) (elliptic.Curve, int, error) {
+	switch name {
+	case CurveP256:
+		return elliptic.P256(), 32, nil
+	case CurveP384:
+		return elliptic.P384(), 48, nil
+	case CurveP521:
+		return elliptic.P521(), 66, nil
+	}
+
+	return nil, 0, ErrCurveUnsupported
+}
+
+func encodeBytes(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func encodeBigInt(i *big.Int) string {
+	return encodeBytes(i.Bytes())
+}
+
+func decodeBytes(data string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, errors.Join(ErrKeyMemberInvalid, err)
+	}
+
+	return decoded, nil
+}
+
+func decodeFixedBytes(data string, size int) ([]byte, error) {
+	decoded, err := decodeBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) != size {
+		return nil, ErrKeyMemberInvalid
+	}
+
+	return decoded, nil
+}
+
+func decodeBigInt(data string) (*big.Int, error) {
+	decoded, err := decodeBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) == 0 {
+		return nil, ErrKeyMemberInvalid
+	}
+
+	return new(big.Int).SetBytes(decoded), nil
+}
@@ -0,0 +1,322 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func Test_Parse_EC(t *testing.T) {
+	// RFC 7517 Appendix A.2
+	var data = `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","d":"870MB6gfuTJ4HtUnUvYMyJpr5eUZNP4Bk43bVdj3eAE","use":"enc","kid":"1"}`
+
+	k, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if k.KeyType() != "EC" {
+		t.Errorf("KeyType got %s, want %s", k.KeyType(), "EC")
+	}
+	if k.KeyID != "1" {
+		t.Errorf("KeyID got %s, want %s", k.KeyID, "1")
+	}
+	if k.Use != "enc" {
+		t.Errorf("Use got %s, want %s", k.Use, "enc")
+	}
+	if !k.IsPrivate() {
+		t.Error("IsPrivate should be true")
+	}
+
+	privateKey, err := k.GetECPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := k.GetECPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte("test-data"))
+	sig, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.VerifyASN1(publicKey, digest[:], sig) {
+		t.Error("Verify fail")
+	}
+
+	encoded, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := `{"kty":"EC","kid":"1","use":"enc","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","d":"870MB6gfuTJ4HtUnUvYMyJpr5eUZNP4Bk43bVdj3eAE"}`
+	if string(encoded) != check {
+		t.Errorf("MarshalJSON got %s, want %s", encoded, check)
+	}
+
+	pub, err := k.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.IsPrivate() {
+		t.Error("Public IsPrivate should be false")
+	}
+
+	check = `{"kty":"EC","kid":"1","use":"enc","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`
+	encoded, err = json.Marshal(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != check {
+		t.Errorf("MarshalJSON got %s, want %s", encoded, check)
+	}
+}
+
+func Test_Parse_OKP(t *testing.T) {
+	// RFC 8037 Appendix A.1
+	var data = `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+
+	k, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if k.KeyType() != "OKP" {
+		t.Errorf("KeyType got %s, want %s", k.KeyType(), "OKP")
+	}
+
+	privateKey, err := k.GetEdPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := k.GetEdPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := ed25519.Sign(privateKey, []byte("test-data"))
+	if !ed25519.Verify(publicKey, []byte("test-data"), sig) {
+		t.Error("Verify fail")
+	}
+
+	_, err = k.GetRSAPublicKey()
+	if !errors.Is(err, ErrNotRSAPublicKey) {
+		t.Errorf("GetRSAPublicKey got %v, want %v", err, ErrNotRSAPublicKey)
+	}
+}
+
+func Test_Parse_Oct(t *testing.T) {
+	// RFC 7517 Appendix A.3
+	var data = `{"kty":"oct","alg":"A128KW","k":"GawgguFyGrWKav7AX4VKUg"}`
+
+	k, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := k.GetHmacKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 16 {
+		t.Errorf("GetHmacKey length got %d, want %d", len(key), 16)
+	}
+	if k.Algorithm != "A128KW" {
+		t.Errorf("Algorithm got %s, want %s", k.Algorithm, "A128KW")
+	}
+
+	_, err = k.Public()
+	if !errors.Is(err, ErrKeyTypeUnsupported) {
+		t.Errorf("Public got %v, want %v", err, ErrKeyTypeUnsupported)
+	}
+}
+
+func Test_JWK_RSA(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := New(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.KeyID = "rsa-key"
+	k.Algorithm = "RS256"
+
+	encoded, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k2, err := Parse(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey2, err := k2.GetRSAPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privateKey.Equal(privateKey2) {
+		t.Error("GetRSAPrivateKey not equal")
+	}
+
+	digest := sha256.Sum256([]byte("test-data"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, privateKey2, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := k2.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := pub.GetRSAPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatal(err)
+	}
+
+	if pub.KeyID != "rsa-key" || pub.Algorithm != "RS256" {
+		t.Errorf("Public KeyID got %s, Algorithm got %s", pub.KeyID, pub.Algorithm)
+	}
+
+	_, err = pub.GetRSAPrivateKey()
+	if !errors.Is(err, ErrNotRSAPrivateKey) {
+		t.Errorf("GetRSAPrivateKey got %v, want %v", err, ErrNotRSAPrivateKey)
+	}
+}
+
+func Test_JWK_RSA_NotPrecomputed(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := json.Marshal(JWK{Key: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the marshaled key must not be changed
+	key := &rsa.PrivateKey{
+		PublicKey: privateKey.PublicKey,
+		D:         privateKey.D,
+		Primes:    privateKey.Primes,
+	}
+
+	encoded, err := json.Marshal(JWK{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Precomputed.Dp != nil || key.Precomputed.Qinv != nil {
+		t.Error("MarshalJSON should not precompute the key")
+	}
+
+	if string(encoded) != string(want) {
+		t.Errorf("MarshalJSON got %s, want %s", encoded, want)
+	}
+}
+
+func Test_JWK_EC_Curves(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			k, err := New(privateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded, err := json.Marshal(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			k2, err := Parse(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			privateKey2, err := k2.GetECPrivateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !privateKey.Equal(privateKey2) {
+				t.Error("GetECPrivateKey not equal")
+			}
+		})
+	}
+}
+
+func Test_JWK_Error(t *testing.T) {
+	_, err := New("test")
+	if !errors.Is(err, ErrKeyTypeUnsupported) {
+		t.Errorf("New got %v, want %v", err, ErrKeyTypeUnsupported)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{
+			name: "kty unsupported",
+			data: `{"kty":"XYZ"}`,
+			err:  ErrKeyTypeUnsupported,
+		},
+		{
+			name: "curve unsupported",
+			data: `{"kty":"EC","crv":"P-192","x":"AA","y":"AA"}`,
+			err:  ErrCurveUnsupported,
+		},
+		{
+			name: "point not on curve",
+			data: `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyQ"}`,
+			err:  ErrKeyInvalid,
+		},
+		{
+			name: "x size invalid",
+			data: `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcH"}`,
+			err:  ErrKeyMemberInvalid,
+		},
+		{
+			name: "d not match",
+			data: `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURA"}`,
+			err:  ErrKeyInvalid,
+		},
+		{
+			name: "oct empty",
+			data: `{"kty":"oct","k":""}`,
+			err:  ErrKeyMemberInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Parse got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
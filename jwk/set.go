@@ -0,0 +1,110 @@
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Set represents a JWK Set, see RFC 7517 section 5.
+type Set struct {
+	Keys []*JWK
+}
+
+// jwk set json members
+type rawSet struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+func NewSet(keys ...*JWK) *Set {
+	return &Set{
+		Keys: keys,
+	}
+}
+
+// ParseSet parses a JSON encoded JWK Set.
+func ParseSet(data []byte) (*Set, error) {
+	var s Set
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Add adds keys to the set.
+func (s *Set) Add(keys ...*JWK) *Set {
+	s.Keys = append(s.Keys, keys...)
+	return s
+}
+
+// Len returns the count of the keys.
+func (s *Set) Len() int {
+	return len(s.Keys)
+}
+
+// LookupKeyID returns the keys with the `kid`.
+func (s *Set) LookupKeyID(kid string) []*JWK {
+	var keys []*JWK
+	for _, k := range s.Keys {
+		if k.KeyID == kid {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// Public returns the set with only the public keys, symmetric keys are removed.
+func (s *Set) Public() *Set {
+	pub := NewSet()
+	for _, k := range s.Keys {
+		if pk, err := k.Public(); err == nil {
+			pub.Add(pk)
+		}
+	}
+
+	return pub
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s Set) MarshalJSON() ([]byte, error) {
+	keys := s.Keys
+	if keys == nil {
+		keys = []*JWK{}
+	}
+
+	return json.Marshal(map[string]any{
+		"keys": keys,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Keys with an unsupported `kty` or curve are ignored, see RFC 7517 section 5.
+func (s *Set) UnmarshalJSON(data []byte) error {
+	var raw rawSet
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.Keys == nil {
+		return ErrKeyMemberInvalid
+	}
+
+	keys := make([]*JWK, 0, len(raw.Keys))
+	for _, rawKey := range raw.Keys {
+		k, err := Parse(rawKey)
+		if err != nil {
+			if errors.Is(err, ErrKeyTypeUnsupported) || errors.Is(err, ErrCurveUnsupported) {
+				continue
+			}
+
+			return err
+		}
+
+		keys = append(keys, k)
+	}
+
+	s.Keys = keys
+
+	return nil
+}
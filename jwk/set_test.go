@@ -0,0 +1,109 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func Test_ParseSet(t *testing.T) {
+	var data = `{"keys":[
+		{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"},
+		{"kty":"oct","alg":"A128KW","k":"GawgguFyGrWKav7AX4VKUg","kid":"2"},
+		{"kty":"XYZ","kid":"3"},
+		{"kty":"OKP","crv":"X448","x":"AA","kid":"4"}
+	]}`
+
+	s, err := ParseSet([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Len() != 2 {
+		t.Errorf("Len got %d, want %d", s.Len(), 2)
+	}
+
+	keys := s.LookupKeyID("2")
+	if len(keys) != 1 {
+		t.Fatalf("LookupKeyID got %d keys, want %d", len(keys), 1)
+	}
+	if keys[0].KeyType() != "oct" {
+		t.Errorf("KeyType got %s, want %s", keys[0].KeyType(), "oct")
+	}
+
+	if len(s.LookupKeyID("3")) != 0 {
+		t.Error("LookupKeyID unsupported key should be ignored")
+	}
+
+	pub := s.Public()
+	if pub.Len() != 1 {
+		t.Errorf("Public Len got %d, want %d", pub.Len(), 1)
+	}
+
+	encoded, err := json.Marshal(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := `{"keys":[{"kty":"EC","kid":"1","use":"enc","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}]}`
+	if string(encoded) != check {
+		t.Errorf("MarshalJSON got %s, want %s", encoded, check)
+	}
+}
+
+func Test_Set(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := New(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.KeyID = "ed"
+
+	s := NewSet().Add(k)
+
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := ParseSet(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := s2.LookupKeyID("ed")
+	if len(keys) != 1 {
+		t.Fatalf("LookupKeyID got %d keys, want %d", len(keys), 1)
+	}
+
+	publicKey2, err := keys[0].GetEdPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publicKey.Equal(publicKey2) {
+		t.Error("GetEdPublicKey not equal")
+	}
+
+	encoded, _ = json.Marshal(NewSet())
+	if string(encoded) != `{"keys":[]}` {
+		t.Errorf("MarshalJSON got %s, want %s", encoded, `{"keys":[]}`)
+	}
+}
+
+func Test_ParseSet_Error(t *testing.T) {
+	_, err := ParseSet([]byte(`{"kty":"oct"}`))
+	if !errors.Is(err, ErrKeyMemberInvalid) {
+		t.Errorf("ParseSet got %v, want %v", err, ErrKeyMemberInvalid)
+	}
+
+	_, err = ParseSet([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}]}`))
+	if !errors.Is(err, ErrKeyMemberInvalid) {
+		t.Errorf("ParseSet got %v, want %v", err, ErrKeyMemberInvalid)
+	}
+}
@@ -0,0 +1,94 @@
+package jwk
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrHashUnavailable        = errors.New("go-jwt: jwk thumbprint hash unavailable")
+	ErrThumbprintSymmetricKey = errors.New("go-jwt: jwk thumbprint kid of a symmetric key is not allowed")
+)
+
+// Thumbprint computes the JWK thumbprint of the key, see RFC 7638.
+// Only the required public members are used, so a private key and
+// its public key have the same thumbprint.
+func (k *JWK) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, ErrHashUnavailable
+	}
+
+	raw, err := k.toRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	// required members in lexicographic order, RFC 7638 section 3.2
+	var members any
+	switch raw.Kty {
+	case KeyTypeRSA:
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{raw.E, raw.Kty, raw.N}
+	case KeyTypeEC:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{raw.Crv, raw.Kty, raw.X, raw.Y}
+	case KeyTypeOKP:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{raw.Crv, raw.Kty, raw.X}
+	case KeyTypeOct:
+		members = struct {
+			K   string `json:"k"`
+			Kty string `json:"kty"`
+		}{raw.K, raw.Kty}
+	default:
+		return nil, ErrKeyTypeUnsupported
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write(data)
+
+	return h.Sum(nil), nil
+}
+
+// ThumbprintString returns the base64url encoded JWK thumbprint of the key.
+func (k *JWK) ThumbprintString(hash crypto.Hash) (string, error) {
+	thumbprint, err := k.Thumbprint(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeBytes(thumbprint), nil
+}
+
+// SetThumbprintKeyID sets the `kid` with the JWK thumbprint of the key.
+// The thumbprint of an `oct` key is a hash of the secret, which can be
+// used to guess the secret, so ErrThumbprintSymmetricKey is returned.
+func (k *JWK) SetThumbprintKeyID(hash crypto.Hash) error {
+	if _, ok := k.Key.([]byte); ok {
+		return ErrThumbprintSymmetricKey
+	}
+
+	kid, err := k.ThumbprintString(hash)
+	if err != nil {
+		return err
+	}
+
+	k.KeyID = kid
+	return nil
+}
@@ -0,0 +1,85 @@
+package jwk
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"errors"
+	"testing"
+)
+
+func Test_Thumbprint(t *testing.T) {
+	// RFC 7638 section 3.1
+	var data = `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+
+	k, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := k.ThumbprintString(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	if got != check {
+		t.Errorf("ThumbprintString got %s, want %s", got, check)
+	}
+
+	if err := k.SetThumbprintKeyID(crypto.SHA256); err != nil {
+		t.Fatal(err)
+	}
+	if k.KeyID != check {
+		t.Errorf("SetThumbprintKeyID got %s, want %s", k.KeyID, check)
+	}
+}
+
+func Test_Thumbprint_PrivateEqualPublic(t *testing.T) {
+	var data = `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+
+	k, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := k.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got1, err := k.ThumbprintString(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got2, err := pub.ThumbprintString(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 8037 Appendix A.3
+	check := "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+	if got1 != check || got2 != check {
+		t.Errorf("ThumbprintString got %s and %s, want %s", got1, got2, check)
+	}
+
+	_, err = k.Thumbprint(crypto.Hash(0))
+	if !errors.Is(err, ErrHashUnavailable) {
+		t.Errorf("Thumbprint got %v, want %v", err, ErrHashUnavailable)
+	}
+}
+
+func Test_SetThumbprintKeyID_Oct(t *testing.T) {
+	k, err := New([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = k.SetThumbprintKeyID(crypto.SHA256)
+	if !errors.Is(err, ErrThumbprintSymmetricKey) {
+		t.Errorf("SetThumbprintKeyID got %v, want %v", err, ErrThumbprintSymmetricKey)
+	}
+	if k.KeyID != "" {
+		t.Errorf("SetThumbprintKeyID got kid %s, want empty", k.KeyID)
+	}
+}
@@ -0,0 +1,46 @@
+package jwks
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+type Option func(*Provider)
+
+// WithHTTPClient sets the http client used to fetch the key set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// WithTTL sets the cache time when the response has no Cache-Control max-age.
+func WithTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.ttl = ttl
+	}
+}
+
+// WithMaxTTL sets the max cache time from the Cache-Control max-age.
+func WithMaxTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.maxTTL = ttl
+	}
+}
+
+// WithMinRefreshInterval sets the min interval between two refreshes,
+// which limits the refreshes caused by unknown `kid`.
+func WithMinRefreshInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		p.minRefreshInterval = interval
+	}
+}
+
+// WithClock sets the clock used by the cache time.
+func WithClock(clock jwt.Clock) Option {
+	return func(p *Provider) {
+		p.clock = clock
+	}
+}
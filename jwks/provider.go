@@ -0,0 +1,361 @@
+package jwks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deatil/go-jwt/jwk"
+	"github.com/deatil/go-jwt/jwt"
+)
+
+var (
+	ErrFetchFail      = errors.New("go-jwt: jwks fetch fail")
+	ErrKeyTypeInvalid = errors.New("go-jwt: jwks key type invalid")
+)
+
+const (
+	// default cache time of the key set
+	DefaultTTL = 5 * time.Minute
+
+	// default max cache time when using the Cache-Control max-age
+	DefaultMaxTTL = 24 * time.Hour
+
+	// default min interval between two refreshes
+	DefaultMinRefreshInterval = 30 * time.Second
+
+	// max size of the fetched key set
+	maxResponseSize = 1 << 20
+)
+
+// Provider loads a JWK Set from a URL or a file, and caches it.
+// The Provider implements jwt.IKeySet, so it can be used with
+// jwt.ParseWithKeySet directly.
+type Provider struct {
+	fetch func(ctx context.Context) ([]byte, http.Header, error)
+
+	client             *http.Client
+	ttl                time.Duration
+	maxTTL             time.Duration
+	minRefreshInterval time.Duration
+	clock              jwt.Clock
+
+	mu          sync.RWMutex
+	keySet      *jwt.KeySet
+	expiresAt   time.Time
+	lastRefresh time.Time
+	lastErr     error
+	call        *refreshCall
+}
+
+// a running refresh, shared by concurrent callers
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// NewProvider returns a Provider which loads the JWK Set from the URL.
+func NewProvider(url string, options ...Option) *Provider {
+	p := newProvider(options...)
+	p.fetch = func(ctx context.Context) ([]byte, http.Header, error) {
+		return p.fetchURL(ctx, url)
+	}
+
+	return p
+}
+
+// NewFileProvider returns a Provider which loads the JWK Set from the file.
+func NewFileProvider(path string, options ...Option) *Provider {
+	p := newProvider(options...)
+	p.fetch = func(ctx context.Context) ([]byte, http.Header, error) {
+		data, err := os.ReadFile(path)
+		return data, nil, err
+	}
+
+	return p
+}
+
+func newProvider(options ...Option) *Provider {
+	p := &Provider{
+		client:             &http.Client{Timeout: 10 * time.Second},
+		ttl:                DefaultTTL,
+		maxTTL:             DefaultMaxTTL,
+		minRefreshInterval: DefaultMinRefreshInterval,
+		clock:              jwt.SystemClock,
+	}
+
+	// Loop through our provider options and apply them
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// LookupKeys implements the jwt.IKeySet interface.
+// The key set is loaded when it is expired, and refreshed when
+// the `kid` is unknown, at most once every min refresh interval.
+func (p *Provider) LookupKeys(kid string) ([]*jwt.KeySetKey, error) {
+	return p.LookupKeysContext(context.Background(), kid)
+}
+
+// LookupKeysContext is like LookupKeys, but stops waiting for
+// the key set fetch when the ctx is done.
+func (p *Provider) LookupKeysContext(ctx context.Context, kid string) ([]*jwt.KeySetKey, error) {
+	keySet, err := p.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := keySet.LookupKeys(kid)
+	if err == nil || kid == "" || !errors.Is(err, jwt.ErrKeySetKeyNotFound) {
+		return keys, err
+	}
+
+	// unknown `kid`, the keys may have been rotated
+	if !p.canRefresh() {
+		return nil, err
+	}
+
+	if err := p.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	keySet = p.keySet
+	p.mu.RUnlock()
+
+	return keySet.LookupKeys(kid)
+}
+
+// KeySet returns the cached key set, which is loaded when expired.
+func (p *Provider) KeySet(ctx context.Context) (*jwt.KeySet, error) {
+	return p.current(ctx)
+}
+
+// Refresh loads the key set now. Concurrent refreshes are coalesced.
+func (p *Provider) Refresh(ctx context.Context) error {
+	return p.refresh(ctx)
+}
+
+// Parse parses the token and verifies it with the provider keys.
+func (p *Provider) Parse(tokenString string, opt ...jwt.ParserOption) (*jwt.Token, error) {
+	return jwt.ParseWithKeySet(tokenString, p, opt...)
+}
+
+// ParseContext is like Parse, but stops waiting for the key set
+// fetch when the ctx is done.
+func (p *Provider) ParseContext(ctx context.Context, tokenString string, opt ...jwt.ParserOption) (*jwt.Token, error) {
+	return jwt.ParseWithKeySet(tokenString, contextKeySet{p, ctx}, opt...)
+}
+
+// the provider key set with the caller ctx
+type contextKeySet struct {
+	p   *Provider
+	ctx context.Context
+}
+
+func (ks contextKeySet) LookupKeys(kid string) ([]*jwt.KeySetKey, error) {
+	return ks.p.LookupKeysContext(ks.ctx, kid)
+}
+
+// KeyFunc returns a key function for jwt.Parse, which selects the key
+// by the token `kid` and `alg` headers with the V key type.
+func KeyFunc[V any](p *Provider) func(t *jwt.Token) (V, error) {
+	return func(t *jwt.Token) (V, error) {
+		var key V
+
+		header, err := t.GetHeader()
+		if err != nil {
+			return key, err
+		}
+
+		kid, err := header.GetKeyID()
+		if err != nil {
+			return key, err
+		}
+
+		alg, err := header.GetAlgorithm()
+		if err != nil {
+			return key, err
+		}
+
+		keys, err := p.LookupKeys(kid)
+		if err != nil {
+			return key, err
+		}
+
+		for _, k := range keys {
+			if v, ok := k.Key().(V); ok && k.Compatible(alg) {
+				return v, nil
+			}
+		}
+
+		return key, ErrKeyTypeInvalid
+	}
+}
+
+// return the cached key set, load it when expired
+func (p *Provider) current(ctx context.Context) (*jwt.KeySet, error) {
+	p.mu.RLock()
+	keySet, expiresAt := p.keySet, p.expiresAt
+	p.mu.RUnlock()
+
+	if keySet != nil && p.clock.Now().Before(expiresAt) {
+		return keySet, nil
+	}
+
+	// a failing upstream is fetched at most once every min refresh interval
+	if !p.canRefresh() {
+		p.mu.RLock()
+		keySet, err := p.keySet, p.lastErr
+		p.mu.RUnlock()
+
+		if keySet != nil {
+			return keySet, nil
+		}
+		if err == nil {
+			err = ErrFetchFail
+		}
+
+		return nil, err
+	}
+
+	err := p.refresh(ctx)
+
+	p.mu.RLock()
+	keySet = p.keySet
+	p.mu.RUnlock()
+
+	// use the stale key set when refresh fail
+	if keySet == nil {
+		return nil, err
+	}
+
+	return keySet, nil
+}
+
+// check the refresh rate limit
+func (p *Provider) canRefresh() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.call != nil || p.clock.Now().Sub(p.lastRefresh) >= p.minRefreshInterval
+}
+
+// refresh the key set, concurrent callers wait the same refresh.
+// The fetch is not canceled with the ctx, as the other callers
+// share it, but each caller stops waiting when its ctx is done.
+func (p *Provider) refresh(ctx context.Context) error {
+	p.mu.Lock()
+	c := p.call
+	if c == nil {
+		c = &refreshCall{
+			done: make(chan struct{}),
+		}
+		p.call = c
+		p.lastRefresh = p.clock.Now()
+
+		go p.doRefresh(context.WithoutCancel(ctx), c)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// load the key set and finish the refresh call
+func (p *Provider) doRefresh(ctx context.Context, c *refreshCall) {
+	keySet, ttl, err := p.load(ctx)
+
+	p.mu.Lock()
+	if err == nil {
+		p.keySet = keySet
+		p.expiresAt = p.clock.Now().Add(ttl)
+	}
+	p.lastErr = err
+	p.call = nil
+	p.mu.Unlock()
+
+	c.err = err
+	close(c.done)
+}
+
+// load the key set and return the cache time
+func (p *Provider) load(ctx context.Context) (*jwt.KeySet, time.Duration, error) {
+	data, header, err := p.fetch(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	set, err := jwk.ParseSet(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return jwt.NewKeySetFromJWKSet(set), p.cacheTTL(header), nil
+}
+
+// fetch the key set from the url
+func (p *Provider) fetchURL(ctx context.Context, url string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, jwt.NewError(fmt.Sprintf("status code %d", resp.StatusCode), ErrFetchFail)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, resp.Header, nil
+}
+
+// return the cache time with the Cache-Control header
+func (p *Provider) cacheTTL(header http.Header) time.Duration {
+	ttl := p.ttl
+	if header == nil {
+		return ttl
+	}
+
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return p.minRefreshInterval
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || seconds < 0 {
+				continue
+			}
+
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return min(max(ttl, p.minRefreshInterval), p.maxTTL)
+}
@@ -0,0 +1,457 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deatil/go-jwt/jwk"
+	"github.com/deatil/go-jwt/jwt"
+)
+
+type testServer struct {
+	*httptest.Server
+
+	lock         sync.Mutex
+	set          []byte
+	cacheControl string
+	status       int
+	block        chan struct{}
+	count        atomic.Int32
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{
+		status: http.StatusOK,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.count.Add(1)
+
+		s.lock.Lock()
+		set, cacheControl, status, block := s.set, s.cacheControl, s.status, s.block
+		s.lock.Unlock()
+
+		if block != nil {
+			<-block
+		}
+
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+
+		w.WriteHeader(status)
+		w.Write(set)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testServer) setKeys(t *testing.T, keys map[string]ed25519.PrivateKey) {
+	set := jwk.NewSet()
+	for kid, key := range keys {
+		k, err := jwk.New(key.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		k.KeyID = kid
+		set.Add(k)
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.lock.Lock()
+	s.set = data
+	s.lock.Unlock()
+}
+
+func newEdKey(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func signToken(t *testing.T, kid string, key ed25519.PrivateKey) string {
+	tokenString, err := jwt.SigningMethodEdDSA.SignWithHeader(jwt.RegisteredHeaders{
+		Type:      "JWT",
+		Algorithm: "EdDSA",
+		KeyID:     kid,
+	}, map[string]string{"sub": "foo"}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenString
+}
+
+func Test_Provider(t *testing.T) {
+	key1 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+
+	clock := jwt.NewFrozenClock(time.Now())
+	p := NewProvider(s.URL, WithTTL(time.Minute), WithClock(clock))
+
+	tokenString := signToken(t, "key1", key1)
+
+	for i := 0; i < 3; i++ {
+		parsed, err := p.Parse(tokenString)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, _ := parsed.GetClaims()
+		sub, _ := claims.GetSubject()
+		if sub != "foo" {
+			t.Errorf("GetSubject got %s, want %s", sub, "foo")
+		}
+	}
+
+	if got := s.count.Load(); got != 1 {
+		t.Errorf("fetch count got %d, want %d", got, 1)
+	}
+
+	// expired, load again
+	clock.Advance(2 * time.Minute)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.count.Load(); got != 2 {
+		t.Errorf("fetch count got %d, want %d", got, 2)
+	}
+}
+
+func Test_Provider_CacheControl(t *testing.T) {
+	key1 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+	s.cacheControl = "public, max-age=3600"
+
+	clock := jwt.NewFrozenClock(time.Now())
+	p := NewProvider(s.URL, WithTTL(time.Minute), WithClock(clock))
+
+	tokenString := signToken(t, "key1", key1)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	// still cached with max-age
+	clock.Advance(30 * time.Minute)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.count.Load(); got != 1 {
+		t.Errorf("fetch count got %d, want %d", got, 1)
+	}
+
+	headers := []struct {
+		cacheControl string
+		want         time.Duration
+	}{
+		{"", time.Minute},
+		{"max-age=600", 10 * time.Minute},
+		{"max-age=1", DefaultMinRefreshInterval},
+		{"max-age=999999", DefaultMaxTTL},
+		{"no-store", DefaultMinRefreshInterval},
+		{"max-age=abc", time.Minute},
+	}
+
+	for _, h := range headers {
+		header := http.Header{}
+		header.Set("Cache-Control", h.cacheControl)
+
+		if got := p.cacheTTL(header); got != h.want {
+			t.Errorf("cacheTTL %q got %s, want %s", h.cacheControl, got, h.want)
+		}
+	}
+}
+
+func Test_Provider_Rotation(t *testing.T) {
+	key1 := newEdKey(t)
+	key2 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+
+	clock := jwt.NewFrozenClock(time.Now())
+	p := NewProvider(s.URL, WithMinRefreshInterval(time.Minute), WithClock(clock))
+
+	if _, err := p.Parse(signToken(t, "key1", key1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// rotate keys
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1, "key2": key2})
+
+	// refresh is rate limited
+	_, err := p.Parse(signToken(t, "key2", key2))
+	if !errors.Is(err, jwt.ErrKeySetKeyNotFound) {
+		t.Errorf("Parse got %v, want %v", err, jwt.ErrKeySetKeyNotFound)
+	}
+
+	clock.Advance(time.Minute)
+
+	if _, err := p.Parse(signToken(t, "key2", key2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// unknown kid after refresh
+	_, err = p.Parse(signToken(t, "key3", key2))
+	if !errors.Is(err, jwt.ErrKeySetKeyNotFound) {
+		t.Errorf("Parse got %v, want %v", err, jwt.ErrKeySetKeyNotFound)
+	}
+
+	if got := s.count.Load(); got != 2 {
+		t.Errorf("fetch count got %d, want %d", got, 2)
+	}
+}
+
+func Test_Provider_Coalesce(t *testing.T) {
+	key1 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+	s.block = make(chan struct{})
+
+	p := NewProvider(s.URL)
+
+	tokenString := signToken(t, "key1", key1)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := p.Parse(tokenString)
+			errs <- err
+		}()
+	}
+
+	// wait the first request
+	for s.count.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(s.block)
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := s.count.Load(); got != 1 {
+		t.Errorf("fetch count got %d, want %d", got, 1)
+	}
+}
+
+func Test_Provider_ContextCancel(t *testing.T) {
+	key1 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+	s.block = make(chan struct{})
+
+	p := NewProvider(s.URL)
+
+	tokenString := signToken(t, "key1", key1)
+
+	// the first caller starts the fetch and is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := p.ParseContext(ctx, tokenString)
+		errs <- err
+	}()
+
+	for s.count.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the waiter stops waiting when its ctx is done
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer waitCancel()
+
+	if _, err := p.LookupKeysContext(waitCtx, "key1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LookupKeysContext got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseContext got %v, want %v", err, context.Canceled)
+	}
+
+	// the shared fetch is not canceled
+	close(s.block)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.count.Load(); got != 1 {
+		t.Errorf("fetch count got %d, want %d", got, 1)
+	}
+}
+
+func Test_Provider_Stale(t *testing.T) {
+	key1 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+
+	clock := jwt.NewFrozenClock(time.Now())
+	p := NewProvider(s.URL, WithClock(clock))
+
+	tokenString := signToken(t, "key1", key1)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	s.lock.Lock()
+	s.status = http.StatusInternalServerError
+	s.lock.Unlock()
+
+	err := p.Refresh(t.Context())
+	if !errors.Is(err, ErrFetchFail) {
+		t.Errorf("Refresh got %v, want %v", err, ErrFetchFail)
+	}
+
+	// the stale key set is used when the refresh fail
+	clock.Advance(DefaultTTL + time.Second)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	p2 := NewProvider(s.URL)
+	if _, err := p2.Parse(tokenString); !errors.Is(err, ErrFetchFail) {
+		t.Errorf("Parse got %v, want %v", err, ErrFetchFail)
+	}
+}
+
+func Test_Provider_FailRateLimit(t *testing.T) {
+	key1 := newEdKey(t)
+
+	s := newTestServer(t)
+	s.setKeys(t, map[string]ed25519.PrivateKey{"key1": key1})
+
+	s.lock.Lock()
+	s.status = http.StatusInternalServerError
+	s.lock.Unlock()
+
+	clock := jwt.NewFrozenClock(time.Now())
+	p := NewProvider(s.URL, WithClock(clock))
+
+	tokenString := signToken(t, "key1", key1)
+
+	// the failing fetch is not repeated on every call
+	for i := 0; i < 3; i++ {
+		if _, err := p.Parse(tokenString); !errors.Is(err, ErrFetchFail) {
+			t.Errorf("Parse got %v, want %v", err, ErrFetchFail)
+		}
+	}
+
+	if got := s.count.Load(); got != 1 {
+		t.Errorf("fetch count got %d, want %d", got, 1)
+	}
+
+	s.lock.Lock()
+	s.status = http.StatusOK
+	s.lock.Unlock()
+
+	clock.Advance(DefaultMinRefreshInterval)
+
+	if _, err := p.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	// the stale key set is used while the upstream is failing
+	s.lock.Lock()
+	s.status = http.StatusInternalServerError
+	s.lock.Unlock()
+
+	clock.Advance(DefaultTTL + time.Second)
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.Parse(tokenString); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := s.count.Load(); got != 3 {
+		t.Errorf("fetch count got %d, want %d", got, 3)
+	}
+}
+
+func Test_FileProvider(t *testing.T) {
+	key1 := newEdKey(t)
+
+	k, err := jwk.New(key1.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.KeyID = "key1"
+
+	data, err := json.Marshal(jwk.NewSet(k))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewFileProvider(path)
+
+	tokenString := signToken(t, "key1", key1)
+
+	parsed, err := jwt.Parse(tokenString, KeyFunc[ed25519.PublicKey](p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := parsed.GetClaims()
+	sub, _ := claims.GetSubject()
+	if sub != "foo" {
+		t.Errorf("GetSubject got %s, want %s", sub, "foo")
+	}
+
+	_, err = jwt.Parse(tokenString, KeyFunc[[]byte](p))
+	if !errors.Is(err, ErrKeyTypeInvalid) {
+		t.Errorf("Parse got %v, want %v", err, ErrKeyTypeInvalid)
+	}
+}
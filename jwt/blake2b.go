@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"bytes"
+	"crypto/rand"
 	"errors"
 	"hash"
 
@@ -47,6 +48,16 @@ func (s *SignBlake2b) SignLength() int {
 	return h.Size()
 }
 
+// GenerateKey returns a random 256 bits key.
+func (s *SignBlake2b) GenerateKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
 // Sign implements token signing for the Signer.
 func (s *SignBlake2b) Sign(msg []byte, key []byte) ([]byte, error) {
 	if len(key)*8 < 256 {
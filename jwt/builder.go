@@ -1,11 +1,110 @@
 package jwt
 
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+var (
+	ErrJWTBuilderClaimsInvalid  = errors.New("go-jwt: builder claims invalid")
+	ErrJWTBuilderClaimsConflict = errors.New("go-jwt: builder registered claim conflict")
+)
+
+// ClaimsConflictPolicy sets how WithClaims merges a registered claim
+// that has been set on the builder, it is applied when the token is built
+type ClaimsConflictPolicy int
+
+const (
+	// the new claim replaces the claim on the builder
+	ClaimsOverwrite ClaimsConflictPolicy = iota
+
+	// the claim on the builder is kept
+	ClaimsKeepExisting
+
+	// GetToken returns ErrJWTBuilderClaimsConflict
+	ClaimsConflictError
+)
+
+// registered claim names
+var registeredClaimNames = []string{
+	RegisteredStdClaims.Audience,
+	RegisteredStdClaims.ExpirationTime,
+	RegisteredStdClaims.ID,
+	RegisteredStdClaims.IssuedAt,
+	RegisteredStdClaims.Issuer,
+	RegisteredStdClaims.NotBefore,
+	RegisteredStdClaims.Subject,
+}
+
+// IDGenerator returns a new token id for the `jti` claim
+type IDGenerator func() (string, error)
+
+// RandomID returns a crypto random token id, 16 bytes base64url encoded
+func RandomID() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// BuilderPolicy sets the default claims when the token is built.
+// The claims already set on the builder are not changed.
+type BuilderPolicy struct {
+	// set the `iat` claim with the clock time
+	IssuedAt bool
+
+	// set the `nbf` claim with the clock time
+	NotBefore bool
+
+	// set the `jti` claim with the id generator
+	ID bool
+
+	// set the `exp` claim with the duration from the clock time, 0 is not set
+	ExpiresIn time.Duration
+
+	// the `jti` generator, RandomID is used when nil
+	IDGenerator IDGenerator
+}
+
 // This class makes easier the token creation process
 type Builder[S any] struct {
 	headers map[string]any
 	claims  map[string]any
 	signer  ISigning[S]
 	encoder IEncoder
+
+	// hash for the `kid` header thumbprint, 0 is not set `kid`
+	kidHash crypto.Hash
+
+	// the clock for the time claims, SystemClock is used when nil
+	clock Clock
+
+	// the `jti` generator, RandomID is used when nil
+	idGenerator IDGenerator
+
+	// set a new `jti` with the id generator when the token is built
+	randomID bool
+
+	// the default claims policy
+	policy BuilderPolicy
+
+	// the registered claims conflict policy for WithClaims
+	conflictPolicy ClaimsConflictPolicy
+
+	// the WithClaims registered claims that conflict with the builder
+	// claims, merged with the conflict policy when the token is built
+	conflictClaims map[string]any
+
+	// the first error when configures the builder
+	err error
 }
 
 func NewBuilder[S any](signer ISigning[S], encoder IEncoder) *Builder[S] {
@@ -17,6 +116,19 @@ func NewBuilder[S any](signer ISigning[S], encoder IEncoder) *Builder[S] {
 	}
 }
 
+// Clone returns a copy of the builder, so a base builder can be used
+// as a template for the per-token builders. The template can be cloned
+// from many goroutines when it is not changed. The nested maps and
+// slices of the headers and claims are copied too.
+func (b *Builder[S]) Clone() *Builder[S] {
+	nb := *b
+	nb.headers = cloneMap(b.headers)
+	nb.claims = cloneMap(b.claims)
+	nb.conflictClaims = cloneMap(b.conflictClaims)
+
+	return &nb
+}
+
 // Configures a header item
 func (b *Builder[S]) WithHeader(name string, value any) *Builder[S] {
 	b.headers[name] = value
@@ -26,6 +138,69 @@ func (b *Builder[S]) WithHeader(name string, value any) *Builder[S] {
 // Configures a claim item
 func (b *Builder[S]) WithClaim(name string, value any) *Builder[S] {
 	b.claims[name] = value
+	delete(b.conflictClaims, name)
+	return b
+}
+
+// Configures the claims from a struct with json tags or a map.
+// The private claims replace the claims on the builder, the
+// registered claims are merged with the conflict policy.
+func (b *Builder[S]) WithClaims(claims any) *Builder[S] {
+	items, err := b.claimsMap(claims)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+
+	for name, value := range items {
+		if _, ok := b.claims[name]; ok && slices.Contains(registeredClaimNames, name) {
+			if b.conflictClaims == nil {
+				b.conflictClaims = map[string]any{}
+			}
+
+			b.conflictClaims[name] = value
+			continue
+		}
+
+		b.claims[name] = value
+	}
+
+	return b
+}
+
+// Configures the registered claims conflict policy used by WithClaims,
+// the policy is applied when the token is built
+func (b *Builder[S]) WithClaimsConflictPolicy(policy ClaimsConflictPolicy) *Builder[S] {
+	b.conflictPolicy = policy
+	return b
+}
+
+// Configures the `kid` header set from the signing key JWK thumbprint
+func (b *Builder[S]) WithKeyIDThumbprint(hash crypto.Hash) *Builder[S] {
+	b.kidHash = hash
+	return b
+}
+
+// Configures the clock used by the time claims
+func (b *Builder[S]) WithClock(clock Clock) *Builder[S] {
+	b.clock = clock
+	return b
+}
+
+// Configures the `jti` generator used by WithRandomID and the policy
+func (b *Builder[S]) WithIDGenerator(generator IDGenerator) *Builder[S] {
+	b.idGenerator = generator
+	return b
+}
+
+// Configures the default claims policy, the policy IDGenerator
+// is used when it is not nil
+func (b *Builder[S]) WithPolicy(policy BuilderPolicy) *Builder[S] {
+	b.policy = policy
+	if policy.IDGenerator != nil {
+		b.idGenerator = policy.IDGenerator
+	}
+
 	return b
 }
 
@@ -43,59 +218,96 @@ func (b *Builder[S]) HeaderAlgo(value any) *Builder[S] {
 
 // Configures the audience
 func (b *Builder[S]) PermittedFor(audiences ClaimStrings) *Builder[S] {
-	b.claims[RegisteredStdClaims.Audience] = audiences
-	return b
+	return b.WithClaim(RegisteredStdClaims.Audience, audiences)
 }
 
 // Configures the expiration time, expirTime
 func (b *Builder[S]) ExpiresAt(expiration *NumericDate) *Builder[S] {
-	b.claims[RegisteredStdClaims.ExpirationTime] = expiration
-	return b
+	return b.WithClaim(RegisteredStdClaims.ExpirationTime, expiration)
+}
+
+// Configures the expiration time with the duration from the clock time
+func (b *Builder[S]) ExpiresIn(d time.Duration) *Builder[S] {
+	return b.ExpiresAt(NewNumericDate(clockNow(b.clock).Add(d)))
 }
 
 // Configures the token id JwtId
 func (b *Builder[S]) IdentifiedBy(id string) *Builder[S] {
-	b.claims[RegisteredStdClaims.ID] = id
+	return b.WithClaim(RegisteredStdClaims.ID, id)
+}
+
+// Configures the token id with the id generator, a new id is generated
+// for each token when it is built and the generator error is returned by
+// GetToken. The token id set on the builder after this call is kept.
+func (b *Builder[S]) WithRandomID() *Builder[S] {
+	delete(b.claims, RegisteredStdClaims.ID)
+	delete(b.conflictClaims, RegisteredStdClaims.ID)
+	b.randomID = true
 	return b
 }
 
 // Configures the time that the token was issued
 func (b *Builder[S]) IssuedAt(issuedAt *NumericDate) *Builder[S] {
-	b.claims[RegisteredStdClaims.IssuedAt] = issuedAt
-	return b
+	return b.WithClaim(RegisteredStdClaims.IssuedAt, issuedAt)
+}
+
+// Configures the time that the token was issued with the clock time
+func (b *Builder[S]) IssuedNow() *Builder[S] {
+	return b.IssuedAt(NewNumericDate(clockNow(b.clock)))
 }
 
 // Configures the issuer
 func (b *Builder[S]) IssuedBy(issuer string) *Builder[S] {
-	b.claims[RegisteredStdClaims.Issuer] = issuer
-	return b
+	return b.WithClaim(RegisteredStdClaims.Issuer, issuer)
 }
 
 // Configures the time before which the token cannot be accepted
 func (b *Builder[S]) CanOnlyBeUsedAfter(notBefore *NumericDate) *Builder[S] {
-	b.claims[RegisteredStdClaims.NotBefore] = notBefore
-	return b
+	return b.WithClaim(RegisteredStdClaims.NotBefore, notBefore)
+}
+
+// Configures the time before which the token cannot be accepted
+// with the duration from the clock time
+func (b *Builder[S]) NotBeforeIn(d time.Duration) *Builder[S] {
+	return b.CanOnlyBeUsedAfter(NewNumericDate(clockNow(b.clock).Add(d)))
 }
 
 // Configures the subject
 func (b *Builder[S]) RelatedTo(subject string) *Builder[S] {
-	b.claims[RegisteredStdClaims.Subject] = subject
-	return b
+	return b.WithClaim(RegisteredStdClaims.Subject, subject)
 }
 
 // Returns the resultant token
 func (b *Builder[S]) GetToken(key S) (*Token, error) {
-	headers := b.headers
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	claims, err := b.mergedClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := maps.Clone(b.headers)
 	if _, ok := headers[RegisteredStdHeaders.Type]; !ok {
 		headers[RegisteredStdHeaders.Type] = "JWT"
 	}
 	if _, ok := headers[RegisteredStdHeaders.Algorithm]; !ok {
 		headers[RegisteredStdHeaders.Algorithm] = b.signer.Alg()
 	}
+	if b.kidHash != 0 {
+		if err := setThumbprintKeyID(headers, key, b.kidHash); err != nil {
+			return nil, err
+		}
+	}
 
 	t := NewToken(b.encoder)
-	t.SetHeader(headers)
-	t.SetClaims(b.claims)
+	if err := t.SetHeader(headers); err != nil {
+		return nil, err
+	}
+	if err := t.SetClaims(claims); err != nil {
+		return nil, err
+	}
 
 	signingString, err := t.SigningString()
 	if err != nil {
@@ -111,3 +323,144 @@ func (b *Builder[S]) GetToken(key S) (*Token, error) {
 
 	return t, nil
 }
+
+// return the claims merged with the conflict policy and the policy default claims
+func (b *Builder[S]) mergedClaims() (map[string]any, error) {
+	p := b.policy
+	if !p.IssuedAt && !p.NotBefore && !p.ID && p.ExpiresIn == 0 && !b.randomID && len(b.conflictClaims) == 0 {
+		return b.claims, nil
+	}
+
+	claims := maps.Clone(b.claims)
+
+	for _, name := range slices.Sorted(maps.Keys(b.conflictClaims)) {
+		switch b.conflictPolicy {
+		case ClaimsKeepExisting:
+			continue
+		case ClaimsConflictError:
+			return nil, NewError(fmt.Sprintf("claim %s has been set", name), ErrJWTBuilderClaimsConflict)
+		}
+
+		claims[name] = b.conflictClaims[name]
+	}
+	now := NewNumericDate(clockNow(b.clock))
+
+	setDefault := func(name string, value any) {
+		if _, ok := claims[name]; !ok {
+			claims[name] = value
+		}
+	}
+
+	if p.IssuedAt {
+		setDefault(RegisteredStdClaims.IssuedAt, now)
+	}
+	if p.NotBefore {
+		setDefault(RegisteredStdClaims.NotBefore, now)
+	}
+	if p.ExpiresIn != 0 {
+		setDefault(RegisteredStdClaims.ExpirationTime, NewNumericDate(now.Add(p.ExpiresIn)))
+	}
+	if _, ok := claims[RegisteredStdClaims.ID]; (p.ID || b.randomID) && !ok {
+		id, err := b.generateID()
+		if err != nil {
+			return nil, err
+		}
+
+		claims[RegisteredStdClaims.ID] = id
+	}
+
+	return claims, nil
+}
+
+// return the claims map from a struct or a map
+func (b *Builder[S]) claimsMap(claims any) (map[string]any, error) {
+	switch c := claims.(type) {
+	case MapClaims:
+		return c, nil
+	case map[string]any:
+		return c, nil
+	}
+
+	encoded, err := b.encoder.JSONEncode(claims)
+	if err != nil {
+		return nil, NewError("", ErrJWTBuilderClaimsInvalid, err)
+	}
+
+	var items map[string]any
+	if err := b.encoder.JSONDecode(encoded, &items); err != nil {
+		return nil, NewError("", ErrJWTBuilderClaimsInvalid, err)
+	}
+
+	return items, nil
+}
+
+// return a new token id
+func (b *Builder[S]) generateID() (string, error) {
+	if b.idGenerator != nil {
+		return b.idGenerator()
+	}
+
+	return RandomID()
+}
+
+// return a copy of the map, the nested maps and slices are copied
+func cloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]any, len(m))
+	for k, v := range m {
+		nm[k] = cloneValue(v)
+	}
+
+	return nm
+}
+
+// return a copy of the maps, slices and claim values
+func cloneValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return cloneMap(val)
+	case MapClaims:
+		return MapClaims(cloneMap(val))
+	case MapHeaders:
+		return MapHeaders(cloneMap(val))
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = cloneValue(item)
+		}
+
+		return items
+	case []string:
+		return slices.Clone(val)
+	case ClaimStrings:
+		val.Value = slices.Clone(val.Value)
+		return val
+	case *ClaimStrings:
+		if val == nil {
+			return val
+		}
+
+		c := *val
+		c.Value = slices.Clone(val.Value)
+		return &c
+	case *NumericDate:
+		if val == nil {
+			return val
+		}
+
+		c := *val
+		return &c
+	}
+
+	return v
+}
+
+// keep the first error
+func (b *Builder[S]) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -44,7 +47,9 @@ func Test_SigningMethodES256_Builder(t *testing.T) {
 	}
 
 	p := SigningMethodES256.New()
-	parsed, err := p.Parse(tokenString, publicKey)
+	parsed, err := p.Parse(tokenString, publicKey, ParserOption{
+		Clock: NewFrozenClock(nbf),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -116,7 +121,9 @@ func Test_SigningMethodES256_Builder2(t *testing.T) {
 	}
 
 	p := SigningMethodES256.New()
-	parsed, err := p.Parse(tokenString, publicKey)
+	parsed, err := p.Parse(tokenString, publicKey, ParserOption{
+		Clock: NewFrozenClock(nbf),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -204,7 +211,9 @@ func Test_SigningMethodES256_Builder3(t *testing.T) {
 	}
 
 	p := SigningMethodES256.New()
-	parsed, err := p.Parse(tokenString, publicKey)
+	parsed, err := p.Parse(tokenString, publicKey, ParserOption{
+		Clock: NewFrozenClock(nbf),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -255,3 +264,421 @@ func Test_SigningMethodES256_Builder3(t *testing.T) {
 		t.Errorf("GetClaims nbf got %f, want %d", claims2["nbf"].(float64), nbf.Unix())
 	}
 }
+
+func Test_Builder_Conveniences(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	s := SigningMethodHS256.New().WithClock(clock)
+
+	token, err := s.Build().
+		IssuedNow().
+		NotBeforeIn(time.Minute).
+		ExpiresIn(time.Hour).
+		WithIDGenerator(func() (string, error) {
+			return "id-1", nil
+		}).
+		WithRandomID().
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := token.GetClaims()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nbf, _ := claims.GetNotBefore()
+	if nbf.Unix() != 1700000060 {
+		t.Errorf("nbf got %d, want %d", nbf.Unix(), 1700000060)
+	}
+
+	jti, _ := claims.GetString("jti")
+	if jti != "id-1" {
+		t.Errorf("jti got %s, want %s", jti, "id-1")
+	}
+
+	// the default generator
+	token, err = s.Build().WithRandomID().GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ = token.GetClaims()
+	jti, _ = claims.GetString("jti")
+	if len(jti) != 22 {
+		t.Errorf("jti length got %d, want %d", len(jti), 22)
+	}
+
+	errGenerate := errors.New("generate fail")
+
+	_, err = s.Build().
+		WithIDGenerator(func() (string, error) {
+			return "", errGenerate
+		}).
+		WithRandomID().
+		GetToken(key)
+	if !errors.Is(err, errGenerate) {
+		t.Errorf("GetToken got %v, want %v", err, errGenerate)
+	}
+}
+
+func Test_Builder_Policy(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	s := SigningMethodHS256.New().
+		WithClock(clock).
+		WithBuilderPolicy(BuilderPolicy{
+			IssuedAt:  true,
+			ID:        true,
+			ExpiresIn: time.Hour,
+			IDGenerator: func() (string, error) {
+				return "policy-id", nil
+			},
+		})
+
+	b := s.Build().IdentifiedBy("own-id")
+
+	token, err := b.GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := token.GetClaims()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iat, _ := claims.GetIssuedAt()
+	if iat.Unix() != 1700000000 {
+		t.Errorf("iat got %d, want %d", iat.Unix(), 1700000000)
+	}
+
+	exp, _ := claims.GetExpirationTime()
+	if exp.Unix() != 1700003600 {
+		t.Errorf("exp got %d, want %d", exp.Unix(), 1700003600)
+	}
+
+	// the claims set on the builder are kept
+	jti, _ := claims.GetString("jti")
+	if jti != "own-id" {
+		t.Errorf("jti got %s, want %s", jti, "own-id")
+	}
+
+	if _, ok := claims["nbf"]; ok {
+		t.Errorf("nbf should not be set")
+	}
+
+	// the policy claims are set when the token is built
+	clock.Advance(time.Minute)
+
+	token, err = s.Build().GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ = token.GetClaims()
+
+	iat, _ = claims.GetIssuedAt()
+	if iat.Unix() != 1700000060 {
+		t.Errorf("iat got %d, want %d", iat.Unix(), 1700000060)
+	}
+
+	jti, _ = claims.GetString("jti")
+	if jti != "policy-id" {
+		t.Errorf("jti got %s, want %s", jti, "policy-id")
+	}
+}
+
+func Test_Builder_WithClaims(t *testing.T) {
+	key := []byte("test-key")
+
+	template := RegisteredClaims{
+		Issuer:    "issuer",
+		Audience:  NewClaimSingleString("example.com"),
+		ExpiresAt: NewNumericDate(time.Unix(1700003600, 0)),
+	}
+
+	type userClaims struct {
+		RegisteredClaims
+		Name  string `json:"name"`
+		Admin bool   `json:"admin,omitempty"`
+	}
+
+	s := SigningMethodHS256.New()
+
+	token, err := s.Build().
+		WithClaims(template).
+		WithClaims(userClaims{
+			RegisteredClaims: RegisteredClaims{
+				Subject: "subject",
+			},
+			Name: "user",
+		}).
+		WithClaims(MapClaims{"role": "admin"}).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims userClaims
+	if err := token.GetClaimsT(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Issuer != "issuer" {
+		t.Errorf("iss got %s, want %s", claims.Issuer, "issuer")
+	}
+	if claims.Subject != "subject" {
+		t.Errorf("sub got %s, want %s", claims.Subject, "subject")
+	}
+	if claims.ExpiresAt.Unix() != 1700003600 {
+		t.Errorf("exp got %d, want %d", claims.ExpiresAt.Unix(), 1700003600)
+	}
+	if claims.Name != "user" {
+		t.Errorf("name got %s, want %s", claims.Name, "user")
+	}
+
+	mapClaims, _ := token.GetClaims()
+	if mapClaims["role"] != "admin" {
+		t.Errorf("role got %v, want %s", mapClaims["role"], "admin")
+	}
+	if _, ok := mapClaims["admin"]; ok {
+		t.Errorf("admin should not be set")
+	}
+}
+
+func Test_Builder_WithClaimsConflict(t *testing.T) {
+	key := []byte("test-key")
+
+	override := RegisteredClaims{
+		Issuer: "other",
+	}
+
+	b := SigningMethodHS256.New().Build()
+
+	// overwrite by default
+	token, err := b.IssuedBy("issuer").
+		WithClaims(override).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := token.GetClaims()
+	if iss, _ := claims.GetIssuer(); iss != "other" {
+		t.Errorf("iss got %s, want %s", iss, "other")
+	}
+
+	token, err = SigningMethodHS256.New().Build().
+		WithClaimsConflictPolicy(ClaimsKeepExisting).
+		IssuedBy("issuer").
+		WithClaims(override).
+		WithClaims(MapClaims{"name": "user"}).
+		WithClaims(MapClaims{"name": "user2"}).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ = token.GetClaims()
+	if iss, _ := claims.GetIssuer(); iss != "issuer" {
+		t.Errorf("iss got %s, want %s", iss, "issuer")
+	}
+
+	// the private claims are replaced
+	if name, _ := claims.GetString("name"); name != "user2" {
+		t.Errorf("name got %s, want %s", name, "user2")
+	}
+
+	_, err = SigningMethodHS256.New().Build().
+		WithClaimsConflictPolicy(ClaimsConflictError).
+		IssuedBy("issuer").
+		WithClaims(override).
+		GetToken(key)
+	if !errors.Is(err, ErrJWTBuilderClaimsConflict) {
+		t.Errorf("GetToken got %v, want %v", err, ErrJWTBuilderClaimsConflict)
+	}
+
+	// the policy not depends on the options order
+	_, err = SigningMethodHS256.New().Build().
+		IssuedBy("issuer").
+		WithClaims(override).
+		WithClaimsConflictPolicy(ClaimsConflictError).
+		GetToken(key)
+	if !errors.Is(err, ErrJWTBuilderClaimsConflict) {
+		t.Errorf("GetToken got %v, want %v", err, ErrJWTBuilderClaimsConflict)
+	}
+
+	token, err = SigningMethodHS256.New().Build().
+		IssuedBy("issuer").
+		WithClaims(override).
+		WithClaimsConflictPolicy(ClaimsKeepExisting).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ = token.GetClaims()
+	if iss, _ := claims.GetIssuer(); iss != "issuer" {
+		t.Errorf("iss got %s, want %s", iss, "issuer")
+	}
+
+	// the claim set after WithClaims is used
+	token, err = SigningMethodHS256.New().Build().
+		WithClaimsConflictPolicy(ClaimsConflictError).
+		IssuedBy("issuer").
+		WithClaims(override).
+		IssuedBy("issuer2").
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ = token.GetClaims()
+	if iss, _ := claims.GetIssuer(); iss != "issuer2" {
+		t.Errorf("iss got %s, want %s", iss, "issuer2")
+	}
+
+	_, err = SigningMethodHS256.New().Build().
+		WithClaims("claims").
+		GetToken(key)
+	if !errors.Is(err, ErrJWTBuilderClaimsInvalid) {
+		t.Errorf("GetToken got %v, want %v", err, ErrJWTBuilderClaimsInvalid)
+	}
+}
+
+func Test_Builder_Clone(t *testing.T) {
+	key := []byte("test-key")
+
+	template := SigningMethodHS256.New().Build().
+		WithHeader("kid", "key1").
+		IssuedBy("issuer").
+		PermittedFor(NewClaimSingleString("example.com"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sub := fmt.Sprintf("user-%d", i)
+
+			token, err := template.Clone().
+				RelatedTo(sub).
+				WithHeader("cty", "JWT").
+				GetToken(key)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			claims, err := token.GetClaims()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if got, _ := claims.GetSubject(); got != sub {
+				errs <- fmt.Errorf("sub got %s, want %s", got, sub)
+				return
+			}
+			if got, _ := claims.GetIssuer(); got != "issuer" {
+				errs <- fmt.Errorf("iss got %s, want %s", got, "issuer")
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// the template is not changed
+	if len(template.headers) != 1 {
+		t.Errorf("template headers got %v, want only kid", template.headers)
+	}
+	if _, ok := template.claims["sub"]; ok {
+		t.Errorf("template sub should not be set")
+	}
+
+	// GetToken not writes the default headers to the builder
+	if _, err := template.GetToken(key); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := template.headers["alg"]; ok {
+		t.Errorf("template alg should not be set")
+	}
+}
+
+func Test_Builder_Clone_RandomID(t *testing.T) {
+	key := []byte("test-key")
+
+	template := SigningMethodHS256.New().Build().
+		WithRandomID().
+		WithClaim("roles", []string{"admin"}).
+		WithClaim("ext", map[string]any{"a": "b"})
+
+	ids := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		token, err := template.Clone().GetToken(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, _ := token.GetClaims()
+		jti, _ := claims.GetString("jti")
+		if jti == "" || ids[jti] {
+			t.Errorf("jti got %q, want a new id", jti)
+		}
+
+		ids[jti] = true
+	}
+
+	// the clone not shares the nested values with the template
+	nb := template.Clone()
+	nb.claims["roles"].([]string)[0] = "user"
+	nb.claims["ext"].(map[string]any)["a"] = "c"
+
+	if got := template.claims["roles"].([]string)[0]; got != "admin" {
+		t.Errorf("template roles got %s, want %s", got, "admin")
+	}
+	if got := template.claims["ext"].(map[string]any)["a"]; got != "b" {
+		t.Errorf("template ext got %s, want %s", got, "b")
+	}
+
+	// the id set after WithRandomID is kept
+	token, err := template.Clone().IdentifiedBy("id-1").GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := token.GetClaims()
+	if jti, _ := claims.GetString("jti"); jti != "id-1" {
+		t.Errorf("jti got %s, want %s", jti, "id-1")
+	}
+}
+
+func Test_Builder_GetTokenEncodeError(t *testing.T) {
+	key := []byte("test-key")
+
+	_, err := SigningMethodHS256.New().Build().
+		WithClaim("ch", make(chan int)).
+		GetToken(key)
+	if err == nil {
+		t.Error("GetToken claims should return error")
+	}
+
+	_, err = SigningMethodHS256.New().Build().
+		WithHeader("ch", make(chan int)).
+		GetToken(key)
+	if err == nil {
+		t.Error("GetToken header should return error")
+	}
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock returns the current time, used by the builder, validator and parser
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function used as a Clock
+type ClockFunc func() time.Time
+
+// Now implements the Clock interface.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock with time.Now
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FrozenClock is a Clock always returns the same time,
+// the time can be changed with Set and Advance.
+type FrozenClock struct {
+	now  time.Time
+	lock sync.RWMutex
+}
+
+func NewFrozenClock(now time.Time) *FrozenClock {
+	return &FrozenClock{
+		now: now,
+	}
+}
+
+// Now implements the Clock interface.
+func (c *FrozenClock) Now() time.Time {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.now
+}
+
+// Set sets the clock time
+func (c *FrozenClock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = now
+}
+
+// Advance moves the clock time forward with the duration
+func (c *FrozenClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// return the clock time, or time.Now when the clock is nil
+func clockNow(clock Clock) time.Time {
+	if clock != nil {
+		return clock.Now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_FrozenClock(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	clock := NewFrozenClock(now)
+	if !clock.Now().Equal(now) {
+		t.Errorf("Now got %s, want %s", clock.Now(), now)
+	}
+
+	clock.Advance(time.Minute)
+	if !clock.Now().Equal(now.Add(time.Minute)) {
+		t.Errorf("Now got %s, want %s", clock.Now(), now.Add(time.Minute))
+	}
+
+	clock.Set(now)
+	if !clock.Now().Equal(now) {
+		t.Errorf("Now got %s, want %s", clock.Now(), now)
+	}
+
+	var c Clock = ClockFunc(func() time.Time {
+		return now
+	})
+	if !c.Now().Equal(now) {
+		t.Errorf("Now got %s, want %s", c.Now(), now)
+	}
+}
+
+func Test_Clock_BuildAndParse(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	s := SigningMethodHS256.New().WithClock(clock)
+
+	token, err := s.Build().
+		IssuedNow().
+		ExpiresIn(time.Hour).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := token.GetClaims()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iat, _ := claims.GetIssuedAt()
+	if iat.Unix() != 1700000000 {
+		t.Errorf("iat got %d, want %d", iat.Unix(), 1700000000)
+	}
+
+	exp, _ := claims.GetExpirationTime()
+	if exp.Unix() != 1700003600 {
+		t.Errorf("exp got %d, want %d", exp.Unix(), 1700003600)
+	}
+
+	tokenString, err := token.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Parse(tokenString, key); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Hour)
+
+	_, err = s.Parse(tokenString, key)
+	if !errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenExpired)
+	}
+
+	_, err = Parse(tokenString, func(t *Token) ([]byte, error) {
+		return key, nil
+	}, ParserOption{
+		Encoder: JWTEncoder,
+		Clock:   clock,
+	})
+	if !errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenExpired)
+	}
+
+	// the parser option clock is used before the JWT clock
+	_, err = s.Parse(tokenString, key, ParserOption{
+		Clock: NewFrozenClock(time.Unix(1700000000, 0)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator, err := NewValidator(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !validator.WithClock(clock).IsExpiredNow() {
+		t.Errorf("IsExpiredNow false")
+	}
+}
@@ -0,0 +1,222 @@
+package jwt
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrJWTConstraintsViolated = errors.New("go-jwt: token constraints violated")
+	ErrJWTTokenInvalidID      = errors.New("go-jwt: token has invalid id")
+)
+
+// Constraint validates a token, and returns the violation error
+type Constraint interface {
+	Validate(t *Token) error
+}
+
+// ConstraintFunc is a function used as a Constraint
+type ConstraintFunc func(t *Token) error
+
+// Validate implements the Constraint interface.
+func (f ConstraintFunc) Validate(t *Token) error {
+	return f(t)
+}
+
+// ConstraintsViolatedError has the violations of the constraints
+type ConstraintsViolatedError struct {
+	Violations []error
+}
+
+// Error returns the violations message.
+func (e *ConstraintsViolatedError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Error())
+	}
+
+	return ErrJWTConstraintsViolated.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns ErrJWTConstraintsViolated and the violations.
+func (e *ConstraintsViolatedError) Unwrap() []error {
+	return append([]error{ErrJWTConstraintsViolated}, e.Violations...)
+}
+
+// Validate validates the token with all the constraints,
+// and returns all the violations in a *ConstraintsViolatedError.
+func Validate(t *Token, constraints ...Constraint) error {
+	var violations []error
+	for _, c := range constraints {
+		if err := c.Validate(t); err != nil {
+			violations = append(violations, err)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ConstraintsViolatedError{
+			Violations: violations,
+		}
+	}
+
+	return nil
+}
+
+// Assert validates the token with the constraints, and returns
+// the first violation in a *ConstraintsViolatedError.
+func Assert(t *Token, constraints ...Constraint) error {
+	for _, c := range constraints {
+		if err := c.Validate(t); err != nil {
+			return &ConstraintsViolatedError{
+				Violations: []error{err},
+			}
+		}
+	}
+
+	return nil
+}
+
+// IssuedBy checks the `iss` claim is one of the issuers.
+func IssuedBy(issuers ...string) Constraint {
+	return ConstraintFunc(func(t *Token) error {
+		claims, err := t.GetClaims()
+		if err != nil {
+			return err
+		}
+
+		iss, err := claims.GetIssuer()
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(issuers, iss) {
+			return ErrJWTTokenInvalidIssuer
+		}
+
+		return nil
+	})
+}
+
+// PermittedFor checks the `aud` claim has the audience.
+func PermittedFor(audience string) Constraint {
+	return ConstraintFunc(func(t *Token) error {
+		claims, err := t.GetClaims()
+		if err != nil {
+			return err
+		}
+
+		aud, err := claims.GetAudience()
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(aud.Value, audience) {
+			return ErrJWTTokenInvalidAudience
+		}
+
+		return nil
+	})
+}
+
+// RelatedTo checks the `sub` claim is the subject.
+func RelatedTo(subject string) Constraint {
+	return ConstraintFunc(func(t *Token) error {
+		claims, err := t.GetClaims()
+		if err != nil {
+			return err
+		}
+
+		sub, err := claims.GetSubject()
+		if err != nil {
+			return err
+		}
+
+		if sub != subject {
+			return ErrJWTTokenInvalidSubject
+		}
+
+		return nil
+	})
+}
+
+// IdentifiedBy checks the `jti` claim is the id.
+func IdentifiedBy(id string) Constraint {
+	return ConstraintFunc(func(t *Token) error {
+		claims, err := t.GetClaims()
+		if err != nil {
+			return err
+		}
+
+		jti, err := claims.GetString(RegisteredStdClaims.ID)
+		if err != nil {
+			return err
+		}
+
+		if jti != id {
+			return ErrJWTTokenInvalidID
+		}
+
+		return nil
+	})
+}
+
+// StrictValidAt checks the token is valid at the clock time, the
+// `exp`, `nbf` and `iat` claims are required.
+func StrictValidAt(clock Clock, leeway time.Duration) Constraint {
+	return validAt(clock, leeway, true)
+}
+
+// LooseValidAt checks the token is valid at the clock time, the
+// `exp`, `nbf` and `iat` claims are only checked when exists.
+func LooseValidAt(clock Clock, leeway time.Duration) Constraint {
+	return validAt(clock, leeway, false)
+}
+
+func validAt(clock Clock, leeway time.Duration, strict bool) Constraint {
+	return ConstraintFunc(func(t *Token) error {
+		claims, err := t.GetClaims()
+		if err != nil {
+			return err
+		}
+
+		opt := ParserOption{
+			Leeway: leeway,
+			Clock:  clock,
+		}
+		if strict {
+			opt.RequiredClaims = []string{"exp", "nbf", "iat"}
+		}
+
+		return validateClaims(claims, claims, opt)
+	})
+}
+
+// SignedWith checks the token `alg` header is the signer algo,
+// and the signature is verified with the key.
+func SignedWith[V any](signer IVerifying[V], key V) Constraint {
+	return ConstraintFunc(func(t *Token) error {
+		header, err := t.GetHeader()
+		if err != nil {
+			return err
+		}
+
+		alg, err := header.GetAlgorithm()
+		if err != nil {
+			return err
+		}
+
+		if alg != signer.Alg() {
+			return ErrJWTAlgoInvalid
+		}
+
+		ok, err := signer.Verify([]byte(t.GetMsg()), t.GetSignature(), key)
+		if !ok || err != nil {
+			return newValidationError(ErrJWTVerifyFail, ErrJWTTokenSignatureInvalid).
+				withCauses(err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Validate(t *testing.T) {
+	key := []byte("test-key")
+	now := time.Unix(1700000000, 0)
+
+	clock := NewFrozenClock(now)
+
+	tokenString, err := SigningMethodHS256.Sign(map[string]any{
+		"iss": "issuer",
+		"aud": []string{"example.com", "other.com"},
+		"sub": "subject",
+		"jti": "id",
+		"exp": now.Unix() + 60,
+		"nbf": now.Unix() - 60,
+		"iat": now.Unix() - 60,
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := NewToken(JWTEncoder)
+	if err := token.Parse(tokenString); err != nil {
+		t.Fatal(err)
+	}
+
+	err = Validate(token,
+		IssuedBy("other", "issuer"),
+		PermittedFor("example.com"),
+		RelatedTo("subject"),
+		IdentifiedBy("id"),
+		StrictValidAt(clock, 0),
+		LooseValidAt(clock, 0),
+		SignedWith(SigningHS256, key),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Validate(token,
+		IssuedBy("other"),
+		PermittedFor("foo.com"),
+		RelatedTo("subject"),
+		IdentifiedBy("id2"),
+		LooseValidAt(NewFrozenClock(now.Add(time.Hour)), 0),
+		SignedWith(SigningHS256, []byte("other-key")),
+		SignedWith(SigningHS384, key),
+	)
+
+	var ve *ConstraintsViolatedError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate got %T, want *ConstraintsViolatedError", err)
+	}
+
+	if len(ve.Violations) != 6 {
+		t.Errorf("Violations got %d, want %d", len(ve.Violations), 6)
+	}
+
+	wants := []error{
+		ErrJWTConstraintsViolated,
+		ErrJWTTokenInvalidIssuer,
+		ErrJWTTokenInvalidAudience,
+		ErrJWTTokenInvalidID,
+		ErrJWTTokenExpired,
+		ErrJWTTokenSignatureInvalid,
+		ErrJWTAlgoInvalid,
+	}
+	for _, want := range wants {
+		if !errors.Is(err, want) {
+			t.Errorf("Validate got %v, want %v", err, want)
+		}
+	}
+
+	// assert mode returns the first violation
+	err = Assert(token,
+		RelatedTo("subject"),
+		IssuedBy("other"),
+		PermittedFor("foo.com"),
+	)
+	if !errors.As(err, &ve) {
+		t.Fatalf("Assert got %T, want *ConstraintsViolatedError", err)
+	}
+
+	if len(ve.Violations) != 1 {
+		t.Errorf("Violations got %d, want %d", len(ve.Violations), 1)
+	}
+	if !errors.Is(err, ErrJWTTokenInvalidIssuer) {
+		t.Errorf("Assert got %v, want %v", err, ErrJWTTokenInvalidIssuer)
+	}
+
+	check := "go-jwt: token constraints violated: go-jwt: token has invalid issuer"
+	if err.Error() != check {
+		t.Errorf("Assert got %s, want %s", err.Error(), check)
+	}
+}
+
+func Test_Validate_Strict(t *testing.T) {
+	token := NewToken(JWTEncoder)
+	token.WithHeader([]byte(`{"alg":"HS256"}`))
+	token.WithClaims([]byte(`{"exp":1700000060}`))
+
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	if err := Validate(token, LooseValidAt(clock, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Validate(token, StrictValidAt(clock, 0))
+	if !errors.Is(err, ErrJWTTokenRequiredClaimMissing) {
+		t.Errorf("Validate got %v, want %v", err, ErrJWTTokenRequiredClaimMissing)
+	}
+
+	clock.Set(time.Unix(1700000070, 0))
+
+	err = Validate(token, LooseValidAt(clock, 20*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Validator_Validate(t *testing.T) {
+	token := NewToken(JWTEncoder)
+	token.WithHeader([]byte(`{"alg":"HS256"}`))
+	token.WithClaims([]byte(`{"sub":"subject","role":"admin"}`))
+
+	validator, err := NewValidator(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errNotAdmin := errors.New("not admin")
+
+	isAdmin := ConstraintFunc(func(t *Token) error {
+		claims, err := t.GetClaims()
+		if err != nil {
+			return err
+		}
+
+		role, _ := claims.GetString("role")
+		if role != "admin" {
+			return errNotAdmin
+		}
+
+		return nil
+	})
+
+	if err := validator.Validate(RelatedTo("subject"), isAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	err = validator.Assert(RelatedTo("other"), isAdmin)
+	if !errors.Is(err, ErrJWTTokenInvalidSubject) {
+		t.Errorf("Assert got %v, want %v", err, ErrJWTTokenInvalidSubject)
+	}
+}
@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+)
+
+var (
+	ErrNotECDHPublicKey  = errors.New("go-jwt: key is not a valid ECDH public key")
+	ErrNotECDHPrivateKey = errors.New("go-jwt: key is not a valid ECDH private key")
+)
+
+// ParseECDHPrivateKeyFromPEM parses a PEM encoded EC or X25519 private key
+func ParseECDHPrivateKeyFromPEM(key []byte) (*ecdh.PrivateKey, error) {
+	der, err := ParsePEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseECDHPrivateKeyFromDer(der)
+}
+
+// ParseECDHPublicKeyFromPEM parses a PEM encoded EC or X25519 public key
+func ParseECDHPublicKeyFromPEM(key []byte) (*ecdh.PublicKey, error) {
+	der, err := ParsePEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseECDHPublicKeyFromDer(der)
+}
+
+func ParseECDHPrivateKeyFromDer(der []byte) (*ecdh.PrivateKey, error) {
+	var err error
+
+	var parsedKey any
+	if parsedKey, err = x509.ParseECPrivateKey(der); err != nil {
+		if parsedKey, err = x509.ParsePKCS8PrivateKey(der); err != nil {
+			return nil, err
+		}
+	}
+
+	return toECDHPrivateKey(parsedKey)
+}
+
+func ParseECDHPublicKeyFromDer(der []byte) (*ecdh.PublicKey, error) {
+	var err error
+
+	var parsedKey any
+	if parsedKey, err = x509.ParsePKIXPublicKey(der); err != nil {
+		if cert, err := x509.ParseCertificate(der); err == nil {
+			parsedKey = cert.PublicKey
+		} else {
+			return nil, err
+		}
+	}
+
+	return toECDHPublicKey(parsedKey)
+}
+
+// convert *ecdsa.PrivateKey or *ecdh.PrivateKey to *ecdh.PrivateKey
+func toECDHPrivateKey(key any) (*ecdh.PrivateKey, error) {
+	switch k := key.(type) {
+	case *ecdh.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		pkey, err := k.ECDH()
+		if err != nil {
+			return nil, NewError("", ErrNotECDHPrivateKey, err)
+		}
+
+		return pkey, nil
+	}
+
+	return nil, ErrNotECDHPrivateKey
+}
+
+// convert *ecdsa.PublicKey or *ecdh.PublicKey to *ecdh.PublicKey
+func toECDHPublicKey(key any) (*ecdh.PublicKey, error) {
+	switch k := key.(type) {
+	case *ecdh.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		pkey, err := k.ECDH()
+		if err != nil {
+			return nil, NewError("", ErrNotECDHPublicKey, err)
+		}
+
+		return pkey, nil
+	}
+
+	return nil, ErrNotECDHPublicKey
+}
@@ -3,6 +3,7 @@ package jwt
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"errors"
 	"math/big"
@@ -29,6 +30,7 @@ func init() {
 var (
 	ErrSignECDSASignLengthInvalid = errors.New("go-jwt: sign length error")
 	ErrSignECDSAVerifyFail        = errors.New("go-jwt: SignECDSA Verify fail")
+	ErrSignECDSAKeySizeInvalid    = errors.New("go-jwt: SignECDSA key size invalid")
 )
 
 // SignECDSA implements the ECDSA family of signing methods.
@@ -56,6 +58,24 @@ func (s *SignECDSA) SignLength() int {
 	return 2 * s.KeySize
 }
 
+// GenerateKey returns a key on the curve of the key size,
+// P-256, P-384 or P-521, see RFC 7518 section 3.4.
+func (s *SignECDSA) GenerateKey() (*ecdsa.PrivateKey, error) {
+	var curve elliptic.Curve
+	switch s.KeySize {
+	case 32:
+		curve = elliptic.P256()
+	case 48:
+		curve = elliptic.P384()
+	case 66:
+		curve = elliptic.P521()
+	default:
+		return nil, ErrSignECDSAKeySizeInvalid
+	}
+
+	return ecdsa.GenerateKey(curve, rand.Reader)
+}
+
 // Sign implements token signing for the Signer.
 func (s *SignECDSA) Sign(msg []byte, key *ecdsa.PrivateKey) ([]byte, error) {
 	hasher := s.Hash.New()
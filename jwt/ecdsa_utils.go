@@ -13,7 +13,18 @@ var (
 
 // ParseECPrivateKeyFromPEM parses a PEM encoded Elliptic Curve Private Key Structure
 func ParseECPrivateKeyFromPEM(key []byte) (*ecdsa.PrivateKey, error) {
-	der, err := ParsePEM(key)
+	der, err := parsePrivateKeyPEM(key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseECPrivateKeyFromDer(der)
+}
+
+// ParseECPrivateKeyFromPEMWithPassword parses a PEM encoded private key,
+// the ENCRYPTED PRIVATE KEY block is decrypted with the password
+func ParseECPrivateKeyFromPEMWithPassword(key []byte, password []byte) (*ecdsa.PrivateKey, error) {
+	der, err := parsePrivateKeyPEM(key, password)
 	if err != nil {
 		return nil, err
 	}
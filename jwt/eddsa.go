@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
 )
 
@@ -45,6 +46,12 @@ func (s *SignEdDSA) SignLength() int {
 	return ed25519.SignatureSize
 }
 
+// GenerateKey returns an Ed25519 key.
+func (s *SignEdDSA) GenerateKey() (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	return key, err
+}
+
 // Sign implements token signing for the Signer.
 func (s *SignEdDSA) Sign(msg []byte, key ed25519.PrivateKey) ([]byte, error) {
 	signed := ed25519.Sign(key, msg)
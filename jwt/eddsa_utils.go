@@ -13,7 +13,18 @@ var (
 
 // ParseEdPrivateKeyFromPEM parses a PEM-encoded Edwards curve private key
 func ParseEdPrivateKeyFromPEM(key []byte) (ed25519.PrivateKey, error) {
-	der, err := ParsePEM(key)
+	der, err := parsePrivateKeyPEM(key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseEdPrivateKeyFromDer(der)
+}
+
+// ParseEdPrivateKeyFromPEMWithPassword parses a PEM encoded private key,
+// the ENCRYPTED PRIVATE KEY block is decrypted with the password
+func ParseEdPrivateKeyFromPEMWithPassword(key []byte, password []byte) (ed25519.PrivateKey, error) {
+	der, err := parsePrivateKeyPEM(key, password)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
@@ -66,6 +67,17 @@ func (s *SignHmac) SignLength() int {
 	return s.Hash().Size()
 }
 
+// GenerateKey returns a random key sized to the hash output,
+// see RFC 7518 section 3.2.
+func (s *SignHmac) GenerateKey() ([]byte, error) {
+	key := make([]byte, s.Hash().Size())
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
 // Sign implements token signing for the Signer.
 func (s *SignHmac) Sign(msg []byte, key []byte) ([]byte, error) {
 	mac := hmac.New(s.Hash, key)
@@ -0,0 +1,357 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"errors"
+)
+
+var (
+	ErrJWETokenInvalid             = errors.New("go-jwt: JWE token invalid")
+	ErrJWEKeyManagementInvalid     = errors.New("go-jwt: JWE key management invalid")
+	ErrJWEContentEncryptionInvalid = errors.New("go-jwt: JWE content encryption invalid")
+	ErrJWEEncInvalid               = errors.New("go-jwt: JWE enc invalid")
+	ErrJWEKeySizeInvalid           = errors.New("go-jwt: JWE key size invalid")
+	ErrJWEDecryptFail              = errors.New("go-jwt: JWE decrypt fail")
+	ErrJWECritUnsupported          = errors.New("go-jwt: JWE crit header unsupported")
+	ErrJWEZipUnsupported           = errors.New("go-jwt: JWE zip header unsupported")
+)
+
+// jwe key management algo interface
+type IKeyAlgo interface {
+	// algo name
+	Alg() string
+}
+
+// jwe key encrypting driver interface
+type IKeyEncrypting[E any] interface {
+	IKeyAlgo
+
+	// EncryptKey returns the content encryption key and the encrypted key.
+	// Key management parameters can be added to the header.
+	EncryptKey(cekSize int, key E, header MapHeaders) (cek []byte, encryptedKey []byte, err error)
+}
+
+// jwe key decrypting driver interface
+type IKeyDecrypting[D any] interface {
+	IKeyAlgo
+
+	// DecryptKey returns the content encryption key.
+	DecryptKey(encryptedKey []byte, cekSize int, key D, header MapHeaders) ([]byte, error)
+}
+
+// jwe key encrypting driver interface, which encodes the key
+// management header parameters with the JWE encoder
+type IKeyEncoderEncrypting[E any] interface {
+	// EncryptKeyWithEncoder is like EncryptKey with the encoder.
+	EncryptKeyWithEncoder(encoder IEncoder, cekSize int, key E, header MapHeaders) (cek []byte, encryptedKey []byte, err error)
+}
+
+// jwe key decrypting driver interface, which decodes the key
+// management header parameters with the JWE encoder
+type IKeyEncoderDecrypting[D any] interface {
+	// DecryptKeyWithEncoder is like DecryptKey with the encoder.
+	DecryptKeyWithEncoder(encoder IEncoder, encryptedKey []byte, cekSize int, key D, header MapHeaders) ([]byte, error)
+}
+
+// jwe key management driver interface
+type IKeyManagement[E any, D any] interface {
+	IKeyAlgo
+
+	// encrypt key function
+	EncryptKey(cekSize int, key E, header MapHeaders) (cek []byte, encryptedKey []byte, err error)
+
+	// decrypt key function
+	DecryptKey(encryptedKey []byte, cekSize int, key D, header MapHeaders) ([]byte, error)
+}
+
+// jwe content encryption driver interface
+type IContentEncryption interface {
+	// enc name
+	Enc() string
+
+	// content encryption key size
+	KeySize() int
+
+	// encrypt function
+	Encrypt(plaintext, cek, aad []byte) (iv, ciphertext, tag []byte, err error)
+
+	// decrypt function
+	Decrypt(iv, ciphertext, tag, cek, aad []byte) ([]byte, error)
+}
+
+type JWE[E any, D any] struct {
+	keyManagement     IKeyManagement[E, D]
+	contentEncryption IContentEncryption
+	encoder           IEncoder
+}
+
+func NewJWE[E any, D any](
+	keyManagement IKeyManagement[E, D],
+	contentEncryption IContentEncryption,
+	encoder IEncoder,
+) JWE[E, D] {
+	if keyManagement == nil {
+		panic(ErrJWEKeyManagementInvalid)
+	}
+	if contentEncryption == nil {
+		panic(ErrJWEContentEncryptionInvalid)
+	}
+	if encoder == nil {
+		panic(ErrJWTEncoderInvalid)
+	}
+
+	return JWE[E, D]{
+		keyManagement:     keyManagement,
+		contentEncryption: contentEncryption,
+		encoder:           encoder,
+	}
+}
+
+// return a clone JWE
+func (jwe JWE[E, D]) New() *JWE[E, D] {
+	return &JWE[E, D]{
+		keyManagement:     jwe.keyManagement,
+		contentEncryption: jwe.contentEncryption,
+		encoder:           jwe.encoder,
+	}
+}
+
+// with new encoder
+func (jwe *JWE[E, D]) WithEncoder(encoder IEncoder) *JWE[E, D] {
+	jwe.encoder = encoder
+	return jwe
+}
+
+// return a JWE key management
+func (jwe *JWE[E, D]) GetKeyManagement() IKeyManagement[E, D] {
+	return jwe.keyManagement
+}
+
+// return a JWE content encryption
+func (jwe *JWE[E, D]) GetContentEncryption() IContentEncryption {
+	return jwe.contentEncryption
+}
+
+// Key management algo name.
+func (jwe *JWE[E, D]) Alg() string {
+	return jwe.keyManagement.Alg()
+}
+
+// Content encryption name.
+func (jwe *JWE[E, D]) Enc() string {
+	return jwe.contentEncryption.Enc()
+}
+
+// Encrypt implements token encryption for the claims.
+func (jwe *JWE[E, D]) Encrypt(claims any, key E) (string, error) {
+	header := RegisteredHeaders{
+		Type: "JWT",
+	}
+
+	return jwe.EncryptWithHeader(header, claims, key)
+}
+
+// EncryptWithHeader implements token encryption for the claims.
+// The `alg` and `enc` header are always set from the JWE.
+func (jwe *JWE[E, D]) EncryptWithHeader(header any, claims any, key E) (string, error) {
+	t := NewEncryptedToken(jwe.encoder)
+	if err := t.SetClaims(claims); err != nil {
+		return "", err
+	}
+
+	return jwe.encrypt(t, header, key)
+}
+
+func (jwe *JWE[E, D]) encrypt(t *EncryptedToken, header any, key E) (string, error) {
+	headers, err := toMapHeaders(jwe.encoder, header)
+	if err != nil {
+		return "", err
+	}
+
+	headers[RegisteredStdHeaders.Algorithm] = jwe.keyManagement.Alg()
+	headers[RegisteredStdHeaders.Encryption] = jwe.contentEncryption.Enc()
+
+	cek, encryptedKey, err := encryptKey[E](jwe.keyManagement, jwe.encoder, jwe.contentEncryption.KeySize(), key, headers)
+	if err != nil {
+		return "", err
+	}
+
+	if err = t.SetHeader(headers); err != nil {
+		return "", err
+	}
+
+	protected, err := t.protectedHeader()
+	if err != nil {
+		return "", err
+	}
+
+	iv, ciphertext, tag, err := jwe.contentEncryption.Encrypt(t.GetClaimsRaw(), cek, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+
+	t.WithEncryptedKey(encryptedKey)
+	t.WithIV(iv)
+	t.WithCiphertext(ciphertext)
+	t.WithTag(tag)
+
+	return t.EncryptedString()
+}
+
+// Decrypt decrypts the token and returns the decrypted token.
+// The claims are validated with the parser option, the option Encoder
+// and ValidMethods are not used, the JWE encoder and algo are used.
+// The returned errors are *ValidationError.
+func (jwe *JWE[E, D]) Decrypt(tokenString string, key D, opt ...ParserOption) (*EncryptedToken, error) {
+	var parserOpt ParserOption
+	if len(opt) > 0 {
+		parserOpt = opt[0]
+	}
+
+	t := NewEncryptedToken(jwe.encoder)
+	if err := t.Parse(tokenString); err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	header, err := t.GetHeader()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	alg, err := header.GetAlgorithm()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+	if alg != jwe.keyManagement.Alg() {
+		return nil, newValidationError(ErrJWTAlgoInvalid, ErrJWTTokenUnverifiable)
+	}
+
+	enc, err := header.GetEncryption()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+	if enc != jwe.contentEncryption.Enc() {
+		return nil, newValidationError(ErrJWEEncInvalid, ErrJWTTokenUnverifiable)
+	}
+
+	err = decryptToken[D](t, header, jwe.keyManagement, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTokenClaims(t, parserOpt); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// decrypt token content with the key management, the returned
+// errors are *ValidationError
+func decryptToken[D any](t *EncryptedToken, header MapHeaders, keyManagement IKeyDecrypting[D], key D) error {
+	typ, err := header.GetType()
+	if err != nil {
+		return newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	// if token type not empty and not equal JWT, return error
+	if len(typ) > 0 && typ != "JWT" {
+		return newValidationError(ErrJWTTypeInvalid, ErrJWTTokenMalformed)
+	}
+
+	// no extension header is understood, RFC 7516 section 4.1.13
+	if _, ok := header[RegisteredStdHeaders.Critical]; ok {
+		return newValidationError(ErrJWECritUnsupported, ErrJWTTokenUnverifiable)
+	}
+
+	// the compressed plaintext is not supported, RFC 7516 section 4.1.3
+	if _, ok := header[RegisteredStdHeaders.Compression]; ok {
+		return newValidationError(ErrJWEZipUnsupported, ErrJWTTokenUnverifiable)
+	}
+
+	enc, err := header.GetEncryption()
+	if err != nil {
+		return newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	contentEncryption := GetContentEncryption(enc)
+	if contentEncryption == nil {
+		return newValidationError(ErrJWEEncInvalid, ErrJWTTokenUnverifiable)
+	}
+
+	cekSize := contentEncryption.KeySize()
+
+	// use a random key when key decrypting fail, so that a wrong key
+	// and a tampered content can not be told apart. RFC 7516 section 11.5
+	cek, keyErr := decryptKey[D](keyManagement, t.encoder, t.GetEncryptedKey(), cekSize, key, header)
+	if keyErr == nil && len(cek) != cekSize {
+		keyErr = ErrJWEKeySizeInvalid
+	}
+	if keyErr != nil {
+		if cek, err = randomBytes(cekSize); err != nil {
+			return newValidationError(err, ErrJWTTokenUnverifiable)
+		}
+	}
+
+	protected, err := t.protectedHeader()
+	if err != nil {
+		return newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	// the authentication tag is checked like a signature
+	plaintext, err := contentEncryption.Decrypt(t.GetIV(), t.GetCiphertext(), t.GetTag(), cek, []byte(protected))
+	if err != nil || keyErr != nil {
+		return newValidationError(ErrJWEDecryptFail, ErrJWTTokenSignatureInvalid)
+	}
+
+	t.WithClaims(plaintext)
+
+	return nil
+}
+
+// encrypt key with the encoder when the key management supports it
+func encryptKey[E any](keyManagement IKeyEncrypting[E], encoder IEncoder, cekSize int, key E, header MapHeaders) ([]byte, []byte, error) {
+	if km, ok := keyManagement.(IKeyEncoderEncrypting[E]); ok {
+		return km.EncryptKeyWithEncoder(encoder, cekSize, key, header)
+	}
+
+	return keyManagement.EncryptKey(cekSize, key, header)
+}
+
+// decrypt key with the encoder when the key management supports it
+func decryptKey[D any](keyManagement IKeyDecrypting[D], encoder IEncoder, encryptedKey []byte, cekSize int, key D, header MapHeaders) ([]byte, error) {
+	if km, ok := keyManagement.(IKeyEncoderDecrypting[D]); ok {
+		return km.DecryptKeyWithEncoder(encoder, encryptedKey, cekSize, key, header)
+	}
+
+	return keyManagement.DecryptKey(encryptedKey, cekSize, key, header)
+}
+
+// convert header to MapHeaders
+func toMapHeaders(encoder IEncoder, header any) (MapHeaders, error) {
+	encoded, err := encoder.JSONEncode(header)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := MapHeaders{}
+	if err = encoder.JSONDecode(encoded, &headers); err != nil {
+		return nil, err
+	}
+
+	if headers == nil {
+		headers = MapHeaders{}
+	}
+
+	return headers, nil
+}
+
+// generate random bytes
+func randomBytes(size int) ([]byte, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+var (
+	ContentEncryptionA128CBCHS256 = NewContentAESCBCHMAC(32, sha256.New, "A128CBC-HS256")
+)
+
+func init() {
+	RegisterContentEncryption(ContentEncryptionA128CBCHS256.Enc(), func() IContentEncryption {
+		return ContentEncryptionA128CBCHS256
+	})
+}
+
+var (
+	ErrContentAESCBCHMACDataInvalid = errors.New("go-jwt: ContentAESCBCHMAC data invalid")
+	ErrContentAESCBCHMACTagInvalid  = errors.New("go-jwt: ContentAESCBCHMAC tag invalid")
+)
+
+// ContentAESCBCHMAC implements the AES CBC HMAC SHA2 family of content encryption.
+// See RFC 7518 section 5.2.
+type ContentAESCBCHMAC struct {
+	Hash    func() hash.Hash
+	Name    string
+	keySize int
+}
+
+func NewContentAESCBCHMAC(keySize int, hash func() hash.Hash, name string) *ContentAESCBCHMAC {
+	return &ContentAESCBCHMAC{
+		Hash:    hash,
+		Name:    name,
+		keySize: keySize,
+	}
+}
+
+// Content encryption name.
+func (c *ContentAESCBCHMAC) Enc() string {
+	return c.Name
+}
+
+// Content encryption key size.
+// The first half is the MAC key and the second half is the ENC key.
+func (c *ContentAESCBCHMAC) KeySize() int {
+	return c.keySize
+}
+
+// Encrypt implements content encryption with a random 128 bits iv.
+func (c *ContentAESCBCHMAC) Encrypt(plaintext, cek, aad []byte) ([]byte, []byte, []byte, error) {
+	if len(cek) != c.keySize {
+		return nil, nil, nil, ErrJWEKeySizeInvalid
+	}
+
+	macKey, encKey := cek[:c.keySize/2], cek[c.keySize/2:]
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	padded := pkcs7Padding(plaintext, aes.BlockSize)
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	tag := c.computeTag(macKey, aad, iv, ciphertext)
+
+	return iv, ciphertext, tag, nil
+}
+
+// Decrypt implements content decryption.
+func (c *ContentAESCBCHMAC) Decrypt(iv, ciphertext, tag, cek, aad []byte) ([]byte, error) {
+	if len(cek) != c.keySize {
+		return nil, ErrJWEKeySizeInvalid
+	}
+
+	if len(iv) != aes.BlockSize ||
+		len(ciphertext) == 0 ||
+		len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrContentAESCBCHMACDataInvalid
+	}
+
+	macKey, encKey := cek[:c.keySize/2], cek[c.keySize/2:]
+
+	// check tag before decrypting
+	checkTag := c.computeTag(macKey, aad, iv, ciphertext)
+	if subtle.ConstantTimeCompare(checkTag, tag) != 1 {
+		return nil, ErrContentAESCBCHMACTagInvalid
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	return pkcs7UnPadding(plaintext, aes.BlockSize)
+}
+
+// compute tag with AAD || IV || Ciphertext || AL, truncated to half of the key size
+func (c *ContentAESCBCHMAC) computeTag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(c.Hash, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+
+	return mac.Sum(nil)[:c.keySize/2]
+}
+
+func pkcs7Padding(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	padText := bytes.Repeat([]byte{byte(padding)}, padding)
+
+	return append(append([]byte{}, data...), padText...)
+}
+
+func pkcs7UnPadding(data []byte, blockSize int) ([]byte, error) {
+	length := len(data)
+	if length == 0 || length%blockSize != 0 {
+		return nil, ErrContentAESCBCHMACDataInvalid
+	}
+
+	padding := int(data[length-1])
+	if padding == 0 || padding > blockSize {
+		return nil, ErrContentAESCBCHMACDataInvalid
+	}
+
+	for _, b := range data[length-padding:] {
+		if int(b) != padding {
+			return nil, ErrContentAESCBCHMACDataInvalid
+		}
+	}
+
+	return data[:length-padding], nil
+}
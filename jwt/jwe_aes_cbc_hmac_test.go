@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_ContentAESCBCHMAC_A128CBCHS256(t *testing.T) {
+	c := ContentEncryptionA128CBCHS256
+
+	if c.Enc() != "A128CBC-HS256" {
+		t.Errorf("Enc got %s, want %s", c.Enc(), "A128CBC-HS256")
+	}
+	if c.KeySize() != 32 {
+		t.Errorf("KeySize got %d, want %d", c.KeySize(), 32)
+	}
+
+	// RFC 7518 Appendix B.1 test case
+	hexDecode := func(s string) []byte {
+		data, _ := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+		return data
+	}
+
+	cek := hexDecode("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	plaintext := []byte("A cipher system must not be required to be secret, and it must be able to fall into the hands of the enemy without inconvenience")
+	iv := hexDecode("1af38c2dc2b96ffdd86694092341bc04")
+	aad := []byte("The second principle of Auguste Kerckhoffs")
+	ciphertext := hexDecode("c80edfa32ddf39d5ef00c0b468834279a2e46a1b8049f792f76bfe54b903a9c9" +
+		"a94ac9b47ad2655c5f10f9aef71427e2fc6f9b3f399a221489f16362c7032336" +
+		"09d45ac69864e3321cf82935ac4096c86e133314c54019e8ca7980dfa4b9cf1b" +
+		"384c486f3a54c51078158ee5d79de59fbd34d848b3d69550a67646344427ade5" +
+		"4b8851ffb598f7f80074b9473c82e2db")
+	tag := hexDecode("652c3fa36b0a7c5b3219fab3a30bc1c4")
+
+	got, err := c.Decrypt(iv, ciphertext, tag, cek, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt got %s, want %s", got, plaintext)
+	}
+
+	tag[0] ^= 0x01
+	_, err = c.Decrypt(iv, ciphertext, tag, cek, aad)
+	if !errors.Is(err, ErrContentAESCBCHMACTagInvalid) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrContentAESCBCHMACTagInvalid)
+	}
+
+	iv2, ciphertext2, tag2, err := c.Encrypt(plaintext, cek, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got2, err := c.Decrypt(iv2, ciphertext2, tag2, cek, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got2, plaintext) {
+		t.Errorf("Decrypt got %s, want %s", got2, plaintext)
+	}
+}
+
+func Test_pkcs7UnPadding_Error(t *testing.T) {
+	data := bytes.Repeat([]byte{0x11}, 16)
+
+	_, err := pkcs7UnPadding(data, 16)
+	if !errors.Is(err, ErrContentAESCBCHMACDataInvalid) {
+		t.Errorf("pkcs7UnPadding got %v, want %v", err, ErrContentAESCBCHMACDataInvalid)
+	}
+
+	data[15] = 0x02
+	_, err = pkcs7UnPadding(data, 16)
+	if !errors.Is(err, ErrContentAESCBCHMACDataInvalid) {
+		t.Errorf("pkcs7UnPadding got %v, want %v", err, ErrContentAESCBCHMACDataInvalid)
+	}
+
+	data[14] = 0x02
+	got, err := pkcs7UnPadding(data, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 14 {
+		t.Errorf("pkcs7UnPadding length got %d, want %d", len(got), 14)
+	}
+}
@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+)
+
+var (
+	ContentEncryptionA128GCM = NewContentAESGCM(16, "A128GCM")
+	ContentEncryptionA256GCM = NewContentAESGCM(32, "A256GCM")
+)
+
+func init() {
+	RegisterContentEncryption(ContentEncryptionA128GCM.Enc(), func() IContentEncryption {
+		return ContentEncryptionA128GCM
+	})
+	RegisterContentEncryption(ContentEncryptionA256GCM.Enc(), func() IContentEncryption {
+		return ContentEncryptionA256GCM
+	})
+}
+
+var ErrContentAESGCMDataInvalid = errors.New("go-jwt: ContentAESGCM data invalid")
+
+// ContentAESGCM implements the AES GCM family of content encryption.
+type ContentAESGCM struct {
+	Name    string
+	keySize int
+}
+
+func NewContentAESGCM(keySize int, name string) *ContentAESGCM {
+	return &ContentAESGCM{
+		Name:    name,
+		keySize: keySize,
+	}
+}
+
+// Content encryption name.
+func (c *ContentAESGCM) Enc() string {
+	return c.Name
+}
+
+// Content encryption key size.
+func (c *ContentAESGCM) KeySize() int {
+	return c.keySize
+}
+
+// Encrypt implements content encryption with a random 96 bits iv.
+func (c *ContentAESGCM) Encrypt(plaintext, cek, aad []byte) ([]byte, []byte, []byte, error) {
+	aead, err := c.newAEAD(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+
+	tagStart := len(sealed) - aead.Overhead()
+
+	return iv, sealed[:tagStart], sealed[tagStart:], nil
+}
+
+// Decrypt implements content decryption.
+func (c *ContentAESGCM) Decrypt(iv, ciphertext, tag, cek, aad []byte) ([]byte, error) {
+	aead, err := c.newAEAD(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, ErrContentAESGCMDataInvalid
+	}
+
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, tag...)
+
+	return aead.Open(nil, iv, sealed, aad)
+}
+
+func (c *ContentAESGCM) newAEAD(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != c.keySize {
+		return nil, ErrJWEKeySizeInvalid
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	KeyManagementA128KW = NewKeyAESKW(16, "A128KW")
+	KeyManagementA256KW = NewKeyAESKW(32, "A256KW")
+)
+
+func init() {
+	RegisterKeyManagement(KeyManagementA128KW.Alg(), func() any {
+		return KeyManagementA128KW
+	})
+	RegisterKeyManagement(KeyManagementA256KW.Alg(), func() any {
+		return KeyManagementA256KW
+	})
+}
+
+var (
+	ErrAESKeyWrapDataInvalid = errors.New("go-jwt: AES key wrap data invalid")
+	ErrAESKeyUnwrapFail      = errors.New("go-jwt: AES key unwrap fail")
+)
+
+// default initial value, RFC 3394 section 2.2.3.1
+var aesKeyWrapDefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// KeyAESKW implements the AES Key Wrap family of key encryption.
+type KeyAESKW struct {
+	Name    string
+	KeySize int
+}
+
+func NewKeyAESKW(keySize int, name string) *KeyAESKW {
+	return &KeyAESKW{
+		Name:    name,
+		KeySize: keySize,
+	}
+}
+
+// Key management algo name.
+func (k *KeyAESKW) Alg() string {
+	return k.Name
+}
+
+// EncryptKey generates a random content encryption key and wraps it with the key.
+func (k *KeyAESKW) EncryptKey(cekSize int, key []byte, header MapHeaders) ([]byte, []byte, error) {
+	if len(key) != k.KeySize {
+		return nil, nil, ErrJWEKeySizeInvalid
+	}
+
+	cek, err := randomBytes(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := aesKeyWrap(key, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// DecryptKey unwraps the content encryption key with the key.
+func (k *KeyAESKW) DecryptKey(encryptedKey []byte, cekSize int, key []byte, header MapHeaders) ([]byte, error) {
+	if len(key) != k.KeySize {
+		return nil, ErrJWEKeySizeInvalid
+	}
+
+	return aesKeyUnwrap(key, encryptedKey)
+}
+
+// aesKeyWrap wraps the cek with the kek, see RFC 3394 section 2.2.1
+func aesKeyWrap(kek, cek []byte) ([]byte, error) {
+	if len(cek) < 16 || len(cek)%8 != 0 {
+		return nil, ErrAESKeyWrapDataInvalid
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(cek) / 8
+
+	r := make([]byte, len(cek))
+	copy(r, cek)
+
+	buf := make([]byte, 16)
+	copy(buf[:8], aesKeyWrapDefaultIV)
+
+	for j := 0; j < 6; j++ {
+		for i := 0; i < n; i++ {
+			copy(buf[8:], r[i*8:(i+1)*8])
+			block.Encrypt(buf, buf)
+
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(buf[:8])^t)
+
+			copy(r[i*8:(i+1)*8], buf[8:])
+		}
+	}
+
+	out := make([]byte, 0, len(cek)+8)
+	out = append(out, buf[:8]...)
+	out = append(out, r...)
+
+	return out, nil
+}
+
+// aesKeyUnwrap unwraps the wrapped key with the kek, see RFC 3394 section 2.2.2
+func aesKeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, ErrAESKeyWrapDataInvalid
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+
+	r := make([]byte, n*8)
+	copy(r, wrapped[8:])
+
+	buf := make([]byte, 16)
+	copy(buf[:8], wrapped[:8])
+
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(buf[:8])^t)
+
+			copy(buf[8:], r[i*8:(i+1)*8])
+			block.Decrypt(buf, buf)
+
+			copy(r[i*8:(i+1)*8], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(buf[:8], aesKeyWrapDefaultIV) != 1 {
+		return nil, ErrAESKeyUnwrapFail
+	}
+
+	return r, nil
+}
@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func Test_aesKeyWrap(t *testing.T) {
+	// RFC 3394 section 4 test vectors
+	tests := []struct {
+		name    string
+		kek     string
+		key     string
+		wrapped string
+	}{
+		{
+			name:    "128 bits kek, 128 bits key",
+			kek:     "000102030405060708090A0B0C0D0E0F",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wrapped: "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+		},
+		{
+			name:    "256 bits kek, 128 bits key",
+			kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wrapped: "64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+		},
+		{
+			name:    "256 bits kek, 256 bits key",
+			kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:     "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			wrapped: "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kek, _ := hex.DecodeString(tt.kek)
+			key, _ := hex.DecodeString(tt.key)
+			wrapped, _ := hex.DecodeString(tt.wrapped)
+
+			got, err := aesKeyWrap(kek, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, wrapped) {
+				t.Errorf("aesKeyWrap got %x, want %x", got, wrapped)
+			}
+
+			unwrapped, err := aesKeyUnwrap(kek, wrapped)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(unwrapped, key) {
+				t.Errorf("aesKeyUnwrap got %x, want %x", unwrapped, key)
+			}
+		})
+	}
+}
+
+func Test_aesKeyUnwrap_Error(t *testing.T) {
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	wrapped, _ := hex.DecodeString("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	wrapped[10] ^= 0x01
+
+	_, err := aesKeyUnwrap(kek, wrapped)
+	if !errors.Is(err, ErrAESKeyUnwrapFail) {
+		t.Errorf("aesKeyUnwrap got %v, want %v", err, ErrAESKeyUnwrapFail)
+	}
+
+	_, err = aesKeyUnwrap(kek, wrapped[:16])
+	if !errors.Is(err, ErrAESKeyWrapDataInvalid) {
+		t.Errorf("aesKeyUnwrap got %v, want %v", err, ErrAESKeyWrapDataInvalid)
+	}
+
+	_, err = aesKeyWrap(kek, []byte("12345678"))
+	if !errors.Is(err, ErrAESKeyWrapDataInvalid) {
+		t.Errorf("aesKeyWrap got %v, want %v", err, ErrAESKeyWrapDataInvalid)
+	}
+}
+
+func Test_KeyAESKW(t *testing.T) {
+	k := KeyManagementA256KW
+
+	if k.Alg() != "A256KW" {
+		t.Errorf("Alg got %s, want %s", k.Alg(), "A256KW")
+	}
+
+	key := []byte("12345678901234567890123456789012")
+
+	cek, encryptedKey, err := k.EncryptKey(32, key, MapHeaders{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cek) != 32 {
+		t.Errorf("EncryptKey cek length got %d, want %d", len(cek), 32)
+	}
+	if len(encryptedKey) != 40 {
+		t.Errorf("EncryptKey encryptedKey length got %d, want %d", len(encryptedKey), 40)
+	}
+
+	cek2, err := k.DecryptKey(encryptedKey, 32, key, MapHeaders{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cek, cek2) {
+		t.Errorf("DecryptKey got %x, want %x", cek2, cek)
+	}
+
+	_, _, err = k.EncryptKey(32, []byte("1234567890123456"), MapHeaders{})
+	if !errors.Is(err, ErrJWEKeySizeInvalid) {
+		t.Errorf("EncryptKey got %v, want %v", err, ErrJWEKeySizeInvalid)
+	}
+}
@@ -0,0 +1,49 @@
+package jwt
+
+var (
+	KeyManagementDir = NewKeyDir("dir")
+)
+
+func init() {
+	RegisterKeyManagement(KeyManagementDir.Alg(), func() any {
+		return KeyManagementDir
+	})
+}
+
+// KeyDir implements direct use of a shared symmetric key as the
+// content encryption key.
+type KeyDir struct {
+	Name string
+}
+
+func NewKeyDir(name string) *KeyDir {
+	return &KeyDir{
+		Name: name,
+	}
+}
+
+// Key management algo name.
+func (k *KeyDir) Alg() string {
+	return k.Name
+}
+
+// EncryptKey returns the key as the content encryption key, the encrypted key is empty.
+func (k *KeyDir) EncryptKey(cekSize int, key []byte, header MapHeaders) ([]byte, []byte, error) {
+	if len(key) != cekSize {
+		return nil, nil, ErrJWEKeySizeInvalid
+	}
+
+	return key, []byte{}, nil
+}
+
+// DecryptKey returns the key as the content encryption key.
+func (k *KeyDir) DecryptKey(encryptedKey []byte, cekSize int, key []byte, header MapHeaders) ([]byte, error) {
+	if len(encryptedKey) > 0 {
+		return nil, ErrJWETokenInvalid
+	}
+	if len(key) != cekSize {
+		return nil, ErrJWEKeySizeInvalid
+	}
+
+	return key, nil
+}
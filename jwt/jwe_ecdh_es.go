@@ -0,0 +1,335 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+var (
+	KeyManagementECDHES       = NewKeyECDHES(0, "ECDH-ES")
+	KeyManagementECDHESA128KW = NewKeyECDHES(16, "ECDH-ES+A128KW")
+	KeyManagementECDHESA256KW = NewKeyECDHES(32, "ECDH-ES+A256KW")
+)
+
+func init() {
+	RegisterKeyManagement(KeyManagementECDHES.Alg(), func() any {
+		return KeyManagementECDHES
+	})
+	RegisterKeyManagement(KeyManagementECDHESA128KW.Alg(), func() any {
+		return KeyManagementECDHESA128KW
+	})
+	RegisterKeyManagement(KeyManagementECDHESA256KW.Alg(), func() any {
+		return KeyManagementECDHESA256KW
+	})
+}
+
+var (
+	ErrJWEEphemeralKeyInvalid  = errors.New("go-jwt: JWE epk header invalid")
+	ErrJWEAgreementInfoInvalid = errors.New("go-jwt: JWE apu or apv header invalid")
+)
+
+// KeyECDHES implements the ECDH-ES family of key agreement.
+// Public keys can be *ecdsa.PublicKey or *ecdh.PublicKey, private keys
+// can be *ecdsa.PrivateKey or *ecdh.PrivateKey.
+// Curves P-256, P-384, P-521 and X25519 are supported.
+type KeyECDHES struct {
+	Name string
+
+	// key wrap key size, 0 is direct key agreement
+	KeySize int
+}
+
+func NewKeyECDHES(keySize int, name string) *KeyECDHES {
+	return &KeyECDHES{
+		Name:    name,
+		KeySize: keySize,
+	}
+}
+
+// Key management algo name.
+func (k *KeyECDHES) Alg() string {
+	return k.Name
+}
+
+// EncryptKey generates an ephemeral key, sets it to the `epk` header
+// and derives the content encryption key or the key wrap key.
+func (k *KeyECDHES) EncryptKey(cekSize int, key crypto.PublicKey, header MapHeaders) ([]byte, []byte, error) {
+	return k.EncryptKeyWithEncoder(JWTEncoder, cekSize, key, header)
+}
+
+// EncryptKeyWithEncoder is like EncryptKey, the `epk`, `apu` and `apv`
+// header parameters are encoded with the encoder.
+func (k *KeyECDHES) EncryptKeyWithEncoder(encoder IEncoder, cekSize int, key crypto.PublicKey, header MapHeaders) ([]byte, []byte, error) {
+	pub, err := toECDHPublicKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ephemeral, err := pub.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	z, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	epk, err := ecdhPublicKeyToJWK(encoder, ephemeral.PublicKey())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header[RegisteredStdHeaders.EphemeralPublicKey] = epk
+
+	derived, err := k.deriveKey(encoder, z, cekSize, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if k.KeySize == 0 {
+		return derived, []byte{}, nil
+	}
+
+	cek, err := randomBytes(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := aesKeyWrap(derived, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// DecryptKey derives the content encryption key or the key wrap key
+// with the `epk` header.
+func (k *KeyECDHES) DecryptKey(encryptedKey []byte, cekSize int, key crypto.PrivateKey, header MapHeaders) ([]byte, error) {
+	return k.DecryptKeyWithEncoder(JWTEncoder, encryptedKey, cekSize, key, header)
+}
+
+// DecryptKeyWithEncoder is like DecryptKey, the `epk`, `apu` and `apv`
+// header parameters are decoded with the encoder.
+func (k *KeyECDHES) DecryptKeyWithEncoder(encoder IEncoder, encryptedKey []byte, cekSize int, key crypto.PrivateKey, header MapHeaders) ([]byte, error) {
+	priv, err := toECDHPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	epk, err := header.GetAny(RegisteredStdHeaders.EphemeralPublicKey)
+	if err != nil {
+		return nil, ErrJWEEphemeralKeyInvalid
+	}
+
+	pub, err := ecdhPublicKeyFromJWK(encoder, epk)
+	if err != nil {
+		return nil, err
+	}
+
+	if pub.Curve() != priv.Curve() {
+		return nil, ErrJWEEphemeralKeyInvalid
+	}
+
+	z, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	derived, err := k.deriveKey(encoder, z, cekSize, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.KeySize == 0 {
+		if len(encryptedKey) > 0 {
+			return nil, ErrJWETokenInvalid
+		}
+
+		return derived, nil
+	}
+
+	return aesKeyUnwrap(derived, encryptedKey)
+}
+
+// derive key with the Concat KDF, see RFC 7518 section 4.6.2
+func (k *KeyECDHES) deriveKey(encoder IEncoder, z []byte, cekSize int, header MapHeaders) ([]byte, error) {
+	algID, keySize := k.Name, k.KeySize
+	if k.KeySize == 0 {
+		enc, err := header.GetEncryption()
+		if err != nil {
+			return nil, err
+		}
+
+		algID, keySize = enc, cekSize
+	}
+
+	apu, err := agreementPartyInfo(encoder, header, RegisteredStdHeaders.AgreementPartyU)
+	if err != nil {
+		return nil, err
+	}
+
+	apv, err := agreementPartyInfo(encoder, header, RegisteredStdHeaders.AgreementPartyV)
+	if err != nil {
+		return nil, err
+	}
+
+	return concatKDF(z, []byte(algID), apu, apv, keySize), nil
+}
+
+// decode `apu` or `apv` header
+func agreementPartyInfo(encoder IEncoder, header MapHeaders, name string) ([]byte, error) {
+	value, err := header.GetString(name)
+	if err != nil {
+		return nil, ErrJWEAgreementInfoInvalid
+	}
+
+	info, err := encoder.Base64URLDecode(value)
+	if err != nil {
+		return nil, NewError("", ErrJWEAgreementInfoInvalid, err)
+	}
+
+	return info, nil
+}
+
+// concatKDF implements the Concat KDF with SHA-256, see NIST SP 800-56A section 5.8.1
+func concatKDF(z, algID, apu, apv []byte, keySize int) []byte {
+	lengthPrefixed := func(data []byte) []byte {
+		buf := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(buf, uint32(len(data)))
+		return append(buf, data...)
+	}
+
+	otherInfo := lengthPrefixed(algID)
+	otherInfo = append(otherInfo, lengthPrefixed(apu)...)
+	otherInfo = append(otherInfo, lengthPrefixed(apv)...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keySize*8))
+
+	out := make([]byte, 0, keySize+sha256.Size)
+	for counter := uint32(1); len(out) < keySize; counter++ {
+		h := sha256.New()
+		binary.Write(h, binary.BigEndian, counter)
+		h.Write(z)
+		h.Write(otherInfo)
+
+		out = h.Sum(out)
+	}
+
+	return out[:keySize]
+}
+
+// ecdh curve name and coordinate size
+var ecdhCurves = []struct {
+	curve ecdh.Curve
+	kty   string
+	crv   string
+	size  int
+}{
+	{ecdh.P256(), "EC", "P-256", 32},
+	{ecdh.P384(), "EC", "P-384", 48},
+	{ecdh.P521(), "EC", "P-521", 66},
+	{ecdh.X25519(), "OKP", "X25519", 32},
+}
+
+// convert ecdh public key to `epk` JWK
+func ecdhPublicKeyToJWK(encoder IEncoder, pub *ecdh.PublicKey) (map[string]string, error) {
+	data := pub.Bytes()
+
+	for _, c := range ecdhCurves {
+		if c.curve != pub.Curve() {
+			continue
+		}
+
+		if c.kty == "OKP" {
+			x, err := encoder.Base64URLEncode(data)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]string{
+				"kty": c.kty,
+				"crv": c.crv,
+				"x":   x,
+			}, nil
+		}
+
+		// uncompressed point 0x04 || X || Y
+		x, err := encoder.Base64URLEncode(data[1 : 1+c.size])
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := encoder.Base64URLEncode(data[1+c.size:])
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]string{
+			"kty": c.kty,
+			"crv": c.crv,
+			"x":   x,
+			"y":   y,
+		}, nil
+	}
+
+	return nil, ErrNotECDHPublicKey
+}
+
+// parse `epk` JWK to ecdh public key
+func ecdhPublicKeyFromJWK(encoder IEncoder, epk any) (*ecdh.PublicKey, error) {
+	jwk, ok := epk.(map[string]any)
+	if !ok {
+		return nil, ErrJWEEphemeralKeyInvalid
+	}
+
+	member := func(name string) ([]byte, error) {
+		value, ok := jwk[name].(string)
+		if !ok {
+			return nil, ErrJWEEphemeralKeyInvalid
+		}
+
+		return encoder.Base64URLDecode(value)
+	}
+
+	kty, _ := jwk["kty"].(string)
+	crv, _ := jwk["crv"].(string)
+
+	for _, c := range ecdhCurves {
+		if c.kty != kty || c.crv != crv {
+			continue
+		}
+
+		x, err := member("x")
+		if err != nil {
+			return nil, NewError("", ErrJWEEphemeralKeyInvalid, err)
+		}
+
+		if c.kty == "OKP" {
+			return c.curve.NewPublicKey(x)
+		}
+
+		y, err := member("y")
+		if err != nil {
+			return nil, NewError("", ErrJWEEphemeralKeyInvalid, err)
+		}
+
+		if len(x) > c.size || len(y) > c.size {
+			return nil, ErrJWEEphemeralKeyInvalid
+		}
+
+		point := make([]byte, 1+2*c.size)
+		point[0] = 4
+		new(big.Int).SetBytes(x).FillBytes(point[1 : 1+c.size])
+		new(big.Int).SetBytes(y).FillBytes(point[1+c.size:])
+
+		return c.curve.NewPublicKey(point)
+	}
+
+	return nil, ErrJWEEphemeralKeyInvalid
+}
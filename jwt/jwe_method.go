@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"sync"
+)
+
+var keyManagements = map[string]func() any{}
+var keyManagementLock = new(sync.RWMutex)
+
+var contentEncryptions = map[string]func() IContentEncryption{}
+var contentEncryptionLock = new(sync.RWMutex)
+
+// RegisterKeyManagement registers the "alg" name and a factory function for key management.
+func RegisterKeyManagement(alg string, f func() any) {
+	keyManagementLock.Lock()
+	defer keyManagementLock.Unlock()
+
+	keyManagements[alg] = f
+}
+
+// GetKeyManagement retrieves a key management from an "alg" string
+func GetKeyManagement(alg string) (method any) {
+	keyManagementLock.RLock()
+	defer keyManagementLock.RUnlock()
+
+	if methodFunc, ok := keyManagements[alg]; ok {
+		method = methodFunc()
+		return
+	}
+
+	return
+}
+
+// GetKeyManagementAlgs returns a list of registered "alg" names
+func GetKeyManagementAlgs() (algs []string) {
+	keyManagementLock.RLock()
+	defer keyManagementLock.RUnlock()
+
+	for alg := range keyManagements {
+		algs = append(algs, alg)
+	}
+
+	return
+}
+
+// RegisterContentEncryption registers the "enc" name and a factory function for content encryption.
+func RegisterContentEncryption(enc string, f func() IContentEncryption) {
+	contentEncryptionLock.Lock()
+	defer contentEncryptionLock.Unlock()
+
+	contentEncryptions[enc] = f
+}
+
+// GetContentEncryption retrieves a content encryption from an "enc" string
+func GetContentEncryption(enc string) (method IContentEncryption) {
+	contentEncryptionLock.RLock()
+	defer contentEncryptionLock.RUnlock()
+
+	if methodFunc, ok := contentEncryptions[enc]; ok {
+		method = methodFunc()
+		return
+	}
+
+	return
+}
+
+// GetContentEncryptionEncs returns a list of registered "enc" names
+func GetContentEncryptionEncs() (encs []string) {
+	contentEncryptionLock.RLock()
+	defer contentEncryptionLock.RUnlock()
+
+	for enc := range contentEncryptions {
+		encs = append(encs, enc)
+	}
+
+	return
+}
@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrJWENestedContentTypeInvalid = errors.New("go-jwt: JWE nested content type invalid")
+
+// the content type of a nested JWT, see RFC 7519 section 5.2
+const nestedContentType = "JWT"
+
+// NestedToken represents a signed JWT wrapped in a JWE.
+type NestedToken struct {
+	outer *EncryptedToken
+	inner *Token
+}
+
+// return the outer encrypted token
+func (t *NestedToken) GetOuter() *EncryptedToken {
+	return t.outer
+}
+
+// return the inner signed token
+func (t *NestedToken) GetInner() *Token {
+	return t.inner
+}
+
+// return the outer token MapHeaders struct
+func (t *NestedToken) GetOuterHeader() (MapHeaders, error) {
+	return t.outer.GetHeader()
+}
+
+// return the inner token MapHeaders struct
+func (t *NestedToken) GetHeader() (MapHeaders, error) {
+	return t.inner.GetHeader()
+}
+
+// return the inner token claims map
+func (t *NestedToken) GetClaims() (MapClaims, error) {
+	return t.inner.GetClaims()
+}
+
+// return the inner token claims with custom type
+func (t *NestedToken) GetClaimsT(dst any) error {
+	return t.inner.GetClaimsT(dst)
+}
+
+// EncryptNested wraps a signed token string in a JWE with the `cty` header JWT.
+func (jwe *JWE[E, D]) EncryptNested(signedToken string, key E) (string, error) {
+	header := RegisteredHeaders{
+		Type:        "JWT",
+		ContentType: nestedContentType,
+	}
+
+	return jwe.EncryptNestedWithHeader(header, signedToken, key)
+}
+
+// EncryptNestedWithHeader wraps a signed token string in a JWE.
+// The `cty` header is always set to JWT.
+func (jwe *JWE[E, D]) EncryptNestedWithHeader(header any, signedToken string, key E) (string, error) {
+	headers, err := toMapHeaders(jwe.encoder, header)
+	if err != nil {
+		return "", err
+	}
+
+	headers[RegisteredStdHeaders.ContentType] = nestedContentType
+
+	t := NewEncryptedToken(jwe.encoder)
+	t.WithClaims([]byte(signedToken))
+
+	return jwe.encrypt(t, headers, key)
+}
+
+// SignAndEncrypt signs the claims with the JWT and then wraps the
+// signed token in a JWE, which makes a nested JWT.
+func SignAndEncrypt[S any, V any, E any, D any](
+	signer *JWT[S, V],
+	encrypter *JWE[E, D],
+	claims any,
+	signKey S,
+	encryptKey E,
+) (string, error) {
+	signedToken, err := signer.Sign(claims, signKey)
+	if err != nil {
+		return "", err
+	}
+
+	return encrypter.EncryptNested(signedToken, encryptKey)
+}
+
+// NestedParserOption has the parser options of a nested token.
+// The Outer ValidMethods are the JWE key management names, and the
+// Inner ValidMethods are the JWS signing method names. The claims are
+// validated with the Inner option, the Outer claims options are not used.
+// JWTEncoder is used when an option has no Encoder.
+type NestedParserOption struct {
+	// the outer JWE parser option
+	Outer ParserOption
+
+	// the inner JWS parser option
+	Inner ParserOption
+}
+
+// ParseNested decrypts the outer JWE with the decryptKeyFunc key, then
+// verifies the inner JWS with the verifyKeyFunc key.
+// The returned errors are *ValidationError.
+func ParseNested[D any, V any](
+	tokenString string,
+	decryptKeyFunc func(t *EncryptedToken) (key D, err error),
+	verifyKeyFunc func(t *Token) (key V, err error),
+	opt ...NestedParserOption,
+) (*NestedToken, error) {
+	var nestedOpt NestedParserOption
+	if len(opt) > 0 {
+		nestedOpt = opt[0]
+	}
+
+	outerOpt, innerOpt := nestedOpt.Outer, nestedOpt.Inner
+
+	// the outer plaintext is the inner JWS, the claims are in the inner token
+	outerOpt.SkipClaimsValidation = true
+	outerOpt.Revocation = nil
+	outerOpt.ReplayCache = nil
+
+	if outerOpt.Encoder == nil {
+		outerOpt.Encoder = JWTEncoder
+	}
+	if innerOpt.Encoder == nil {
+		innerOpt.Encoder = JWTEncoder
+	}
+
+	outer, err := Decrypt[D](tokenString, decryptKeyFunc, outerOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := outer.GetHeader()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	cty, err := header.GetContentType()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	// the `cty` value is compared case insensitive, RFC 7515 section 4.1.10
+	if !strings.EqualFold(cty, nestedContentType) {
+		return nil, newValidationError(ErrJWENestedContentTypeInvalid, ErrJWTTokenMalformed)
+	}
+
+	inner, err := Parse[V](string(outer.GetClaimsRaw()), verifyKeyFunc, innerOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NestedToken{
+		outer: outer,
+		inner: inner,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func Test_SignAndEncrypt_ParseNested(t *testing.T) {
+	signKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := map[string]string{
+		"aud": "example.com",
+		"sub": "foo",
+	}
+
+	s := SigningMethodES256.New()
+	e := NewJWE[*rsa.PublicKey, *rsa.PrivateKey](KeyManagementRSAOAEP256, ContentEncryptionA256GCM, JWTEncoder).New()
+
+	tokenString, err := SignAndEncrypt(s, e, claims, signKey, &encryptKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nested, err := ParseNested[*rsa.PrivateKey, *ecdsa.PublicKey](tokenString, func(t *EncryptedToken) (*rsa.PrivateKey, error) {
+		return encryptKey, nil
+	}, func(t *Token) (*ecdsa.PublicKey, error) {
+		return &signKey.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outerHeader, err := nested.GetOuterHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cty, _ := outerHeader.GetContentType()
+	if cty != "JWT" {
+		t.Errorf("outer cty got %s, want %s", cty, "JWT")
+	}
+	alg, _ := outerHeader.GetAlgorithm()
+	if alg != "RSA-OAEP-256" {
+		t.Errorf("outer alg got %s, want %s", alg, "RSA-OAEP-256")
+	}
+
+	innerHeader, err := nested.GetHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	alg, _ = innerHeader.GetAlgorithm()
+	if alg != "ES256" {
+		t.Errorf("inner alg got %s, want %s", alg, "ES256")
+	}
+
+	if nested.GetOuter() == nil || nested.GetInner() == nil {
+		t.Error("nested token outer and inner should not be nil")
+	}
+
+	claims2, err := nested.GetClaims()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims2["sub"].(string) != claims["sub"] {
+		t.Errorf("GetClaims sub got %s, want %s", claims2["sub"].(string), claims["sub"])
+	}
+
+	var claims3 RegisteredClaims
+	if err := nested.GetClaimsT(&claims3); err != nil {
+		t.Fatal(err)
+	}
+	if claims3.Subject != claims["sub"] {
+		t.Errorf("GetClaimsT sub got %s, want %s", claims3.Subject, claims["sub"])
+	}
+
+	// verify with a wrong key
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseNested[*rsa.PrivateKey, *ecdsa.PublicKey](tokenString, func(t *EncryptedToken) (*rsa.PrivateKey, error) {
+		return encryptKey, nil
+	}, func(t *Token) (*ecdsa.PublicKey, error) {
+		return &otherKey.PublicKey, nil
+	})
+	if !errors.Is(err, ErrJWTVerifyFail) {
+		t.Errorf("ParseNested got %v, want %v", err, ErrJWTVerifyFail)
+	}
+}
+
+func Test_EncryptNested_Builder(t *testing.T) {
+	signKey := []byte("test-key")
+	encryptKey := []byte("1234567890123456")
+
+	s := SigningMethodHS256.New()
+
+	token, err := s.Build().
+		HeaderType("JWT").
+		WithHeader("kid", "sign-key").
+		IssuedBy("issuer").
+		GetToken(signKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedToken, err := token.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128GCM, JWTEncoder).New()
+
+	tokenString, err := e.EncryptNestedWithHeader(map[string]string{
+		"kid": "encrypt-key",
+		"cty": "text",
+	}, signedToken, encryptKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nested, err := ParseNested[[]byte, []byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+		return encryptKey, nil
+	}, func(t *Token) ([]byte, error) {
+		return signKey, nil
+	}, NestedParserOption{
+		Outer: ParserOption{
+			ValidMethods: []string{"A128KW"},
+		},
+		Inner: ParserOption{
+			ValidMethods: []string{"HS256"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outerHeader, _ := nested.GetOuterHeader()
+	kid, _ := outerHeader.GetKeyID()
+	if kid != "encrypt-key" {
+		t.Errorf("outer kid got %s, want %s", kid, "encrypt-key")
+	}
+	cty, _ := outerHeader.GetContentType()
+	if cty != "JWT" {
+		t.Errorf("outer cty got %s, want %s", cty, "JWT")
+	}
+
+	innerHeader, _ := nested.GetHeader()
+	kid, _ = innerHeader.GetKeyID()
+	if kid != "sign-key" {
+		t.Errorf("inner kid got %s, want %s", kid, "sign-key")
+	}
+
+	claims, _ := nested.GetClaims()
+	iss, _ := claims.GetIssuer()
+	if iss != "issuer" {
+		t.Errorf("GetIssuer got %s, want %s", iss, "issuer")
+	}
+
+	// the key management is not a valid inner method
+	_, err = ParseNested[[]byte, []byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+		return encryptKey, nil
+	}, func(t *Token) ([]byte, error) {
+		return signKey, nil
+	}, NestedParserOption{
+		Outer: ParserOption{
+			ValidMethods: []string{"A128KW"},
+		},
+		Inner: ParserOption{
+			ValidMethods: []string{"A128KW"},
+		},
+	})
+	if !errors.Is(err, ErrJWTTokenSignatureInvalid) {
+		t.Errorf("ParseNested got %v, want %v", err, ErrJWTTokenSignatureInvalid)
+	}
+
+	_, err = ParseNested[[]byte, []byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+		return encryptKey, nil
+	}, func(t *Token) ([]byte, error) {
+		return signKey, nil
+	}, NestedParserOption{
+		Outer: ParserOption{
+			ValidMethods: []string{"HS256"},
+		},
+	})
+	if !errors.Is(err, ErrJWEKeyManagementInvalid) {
+		t.Errorf("ParseNested got %v, want %v", err, ErrJWEKeyManagementInvalid)
+	}
+}
+
+func Test_ParseNested_ContentTypeInvalid(t *testing.T) {
+	encryptKey := []byte("1234567890123456")
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128GCM, JWTEncoder).New()
+
+	tokenString, err := e.Encrypt(map[string]string{"sub": "foo"}, encryptKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseNested[[]byte, []byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+		return encryptKey, nil
+	}, func(t *Token) ([]byte, error) {
+		return []byte("test-key"), nil
+	})
+	if !errors.Is(err, ErrJWENestedContentTypeInvalid) {
+		t.Errorf("ParseNested got %v, want %v", err, ErrJWENestedContentTypeInvalid)
+	}
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"fmt"
+)
+
+// Decrypt decrypts the JWE token and returns the decrypted token.
+// The key management is selected by the token `alg` header, and the
+// decrypted claims are validated with the parser option.
+// The returned errors are *ValidationError.
+func Decrypt[D any](tokenString string, keyFunc func(t *EncryptedToken) (key D, err error), opt ...ParserOption) (*EncryptedToken, error) {
+	var parserOpt ParserOption
+	if len(opt) > 0 {
+		parserOpt = opt[0]
+	} else {
+		parserOpt = ParserOption{
+			Encoder: JWTEncoder,
+		}
+	}
+
+	// if not set encoder, return error
+	if parserOpt.Encoder == nil {
+		return nil, newValidationError(ErrJWTEncoderInvalid, ErrJWTTokenUnverifiable)
+	}
+
+	t := NewEncryptedToken(parserOpt.Encoder)
+	if err := t.Parse(tokenString); err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	header, err := t.GetHeader()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	alg, err := header.GetAlgorithm()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	// Verify key management is in the required set
+	if parserOpt.ValidMethods != nil {
+		var keyManagementValid = false
+
+		for _, m := range parserOpt.ValidMethods {
+			if m == alg {
+				keyManagementValid = true
+				break
+			}
+		}
+
+		if !keyManagementValid {
+			err := NewError(fmt.Sprintf("key management %v is invalid", alg), ErrJWEKeyManagementInvalid)
+			return nil, newValidationError(err, ErrJWTTokenUnverifiable)
+		}
+	}
+
+	keyManagement := GetKeyManagement(alg)
+	if keyManagement == nil {
+		return nil, newValidationError(ErrJWTMethodExists, ErrJWTTokenUnverifiable)
+	}
+
+	decrypter, ok := keyManagement.(IKeyDecrypting[D])
+	if !ok {
+		return nil, newValidationError(ErrJWTMethodInvalid, ErrJWTTokenUnverifiable)
+	}
+
+	key, err := keyFunc(t)
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenUnverifiable)
+	}
+
+	err = decryptToken[D](t, header, decrypter, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTokenClaims(t, parserOpt); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
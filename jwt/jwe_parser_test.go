@@ -0,0 +1,153 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Decrypt(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := map[string]string{
+		"aud": "example.com",
+		"sub": "foo",
+	}
+
+	e := NewJWE[*rsa.PublicKey, *rsa.PrivateKey](KeyManagementRSAOAEP256, ContentEncryptionA128CBCHS256, JWTEncoder).New()
+
+	tokenString, err := e.Encrypt(claims, &privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := Decrypt[*rsa.PrivateKey](tokenString, func(t *EncryptedToken) (*rsa.PrivateKey, error) {
+		return privateKey, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims2, err := decrypted.GetClaims()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims2["aud"].(string) != claims["aud"] {
+		t.Errorf("GetClaims aud got %s, want %s", claims2["aud"].(string), claims["aud"])
+	}
+
+	_, err = Decrypt[*rsa.PrivateKey](tokenString, func(t *EncryptedToken) (*rsa.PrivateKey, error) {
+		return privateKey, nil
+	}, ParserOption{
+		Encoder:      JWTEncoder,
+		ValidMethods: []string{"RSA-OAEP"},
+	})
+	if !errors.Is(err, ErrJWEKeyManagementInvalid) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWEKeyManagementInvalid)
+	}
+
+	_, err = Decrypt[[]byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+		return []byte("test"), nil
+	})
+	if !errors.Is(err, ErrJWTMethodInvalid) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWTMethodInvalid)
+	}
+
+	_, err = Decrypt[*rsa.PrivateKey](tokenString, func(t *EncryptedToken) (*rsa.PrivateKey, error) {
+		return privateKey, nil
+	}, ParserOption{})
+	if !errors.Is(err, ErrJWTEncoderInvalid) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWTEncoderInvalid)
+	}
+
+	checkErr := errors.New("key not found")
+	_, err = Decrypt[*rsa.PrivateKey](tokenString, func(t *EncryptedToken) (*rsa.PrivateKey, error) {
+		return nil, checkErr
+	})
+	if !errors.Is(err, checkErr) {
+		t.Errorf("Decrypt got %v, want %v", err, checkErr)
+	}
+}
+
+func Test_Decrypt_EncInvalid(t *testing.T) {
+	key := []byte("1234567890123456")
+
+	token := NewEncryptedToken(JWTEncoder)
+	token.SetHeader(map[string]string{
+		"alg": "dir",
+		"enc": "A999GCM",
+	})
+
+	tokenString, err := token.EncryptedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Decrypt[[]byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+		return key, nil
+	})
+	if !errors.Is(err, ErrJWEEncInvalid) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWEEncInvalid)
+	}
+}
+
+func Test_Decrypt_ValidateClaims(t *testing.T) {
+	key := []byte("1234567890123456")
+	now := time.Unix(1700000000, 0)
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128GCM, JWTEncoder)
+
+	tokenString, err := e.Encrypt(map[string]any{
+		"aud": "example.com",
+		"exp": now.Add(time.Hour).Unix(),
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFunc := func(t *EncryptedToken) ([]byte, error) {
+		return key, nil
+	}
+
+	_, err = Decrypt[[]byte](tokenString, keyFunc, ParserOption{
+		Encoder: JWTEncoder,
+		Clock:   NewFrozenClock(now),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Decrypt[[]byte](tokenString, keyFunc, ParserOption{
+		Encoder: JWTEncoder,
+		Clock:   NewFrozenClock(now.Add(2 * time.Hour)),
+	})
+	if !errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWTTokenExpired)
+	}
+
+	_, err = e.Decrypt(tokenString, key, ParserOption{
+		Audience: []string{"other.com"},
+		Clock:    NewFrozenClock(now),
+	})
+	if !errors.Is(err, ErrJWTTokenInvalidAudience) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWTTokenInvalidAudience)
+	}
+
+	// the system clock is used by default
+	_, err = e.Decrypt(tokenString, key)
+	if !errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWTTokenExpired)
+	}
+
+	_, err = e.Decrypt(tokenString, key, ParserOption{
+		SkipClaimsValidation: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
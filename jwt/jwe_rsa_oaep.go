@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+)
+
+var (
+	KeyManagementRSAOAEP    = NewKeyRSAOAEP(crypto.SHA1, "RSA-OAEP")
+	KeyManagementRSAOAEP256 = NewKeyRSAOAEP(crypto.SHA256, "RSA-OAEP-256")
+)
+
+func init() {
+	RegisterKeyManagement(KeyManagementRSAOAEP.Alg(), func() any {
+		return KeyManagementRSAOAEP
+	})
+	RegisterKeyManagement(KeyManagementRSAOAEP256.Alg(), func() any {
+		return KeyManagementRSAOAEP256
+	})
+}
+
+// KeyRSAOAEP implements the RSAES-OAEP family of key encryption.
+type KeyRSAOAEP struct {
+	Name string
+	Hash crypto.Hash
+}
+
+func NewKeyRSAOAEP(hash crypto.Hash, name string) *KeyRSAOAEP {
+	return &KeyRSAOAEP{
+		Name: name,
+		Hash: hash,
+	}
+}
+
+// Key management algo name.
+func (k *KeyRSAOAEP) Alg() string {
+	return k.Name
+}
+
+// EncryptKey generates a random content encryption key and encrypts it with the public key.
+func (k *KeyRSAOAEP) EncryptKey(cekSize int, key *rsa.PublicKey, header MapHeaders) ([]byte, []byte, error) {
+	cek, err := randomBytes(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(k.Hash.New(), rand.Reader, key, cek, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// DecryptKey decrypts the content encryption key with the private key.
+func (k *KeyRSAOAEP) DecryptKey(encryptedKey []byte, cekSize int, key *rsa.PrivateKey, header MapHeaders) ([]byte, error) {
+	return rsa.DecryptOAEP(k.Hash.New(), nil, key, encryptedKey, nil)
+}
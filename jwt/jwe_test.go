@@ -0,0 +1,295 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func Test_JWE_Decrypt_RFC7516_A3(t *testing.T) {
+	// RFC 7516 Appendix A.3, A128KW and A128CBC-HS256
+	var tokenString = "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+
+	key, err := JWTEncoder.Base64URLDecode("GawgguFyGrWKav7AX4VKUg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128CBCHS256, JWTEncoder)
+
+	// the plaintext is not the JSON claims
+	decrypted, err := e.Decrypt(tokenString, key, ParserOption{
+		SkipClaimsValidation: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := "Live long and prosper."
+	if string(decrypted.GetClaimsRaw()) != check {
+		t.Errorf("GetClaimsRaw got %s, want %s", decrypted.GetClaimsRaw(), check)
+	}
+
+	key[0] ^= 0x01
+	_, err = e.Decrypt(tokenString, key)
+	if !errors.Is(err, ErrJWEDecryptFail) {
+		t.Errorf("Decrypt got %v, want %v", err, ErrJWEDecryptFail)
+	}
+}
+
+func Test_JWE_Symmetric(t *testing.T) {
+	claims := map[string]string{
+		"aud": "example.com",
+		"sub": "foo",
+	}
+
+	tests := []struct {
+		name              string
+		keyManagement     IKeyManagement[[]byte, []byte]
+		contentEncryption IContentEncryption
+		keySize           int
+	}{
+		{"dir A128GCM", KeyManagementDir, ContentEncryptionA128GCM, 16},
+		{"dir A256GCM", KeyManagementDir, ContentEncryptionA256GCM, 32},
+		{"dir A128CBC-HS256", KeyManagementDir, ContentEncryptionA128CBCHS256, 32},
+		{"A128KW A128GCM", KeyManagementA128KW, ContentEncryptionA128GCM, 16},
+		{"A128KW A256GCM", KeyManagementA128KW, ContentEncryptionA256GCM, 16},
+		{"A256KW A128CBC-HS256", KeyManagementA256KW, ContentEncryptionA128CBCHS256, 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := make([]byte, tt.keySize)
+			rand.Read(key)
+
+			e := NewJWE[[]byte, []byte](tt.keyManagement, tt.contentEncryption, JWTEncoder).New()
+
+			tokenString, err := e.Encrypt(claims, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decrypted, err := e.Decrypt(tokenString, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if decrypted.GetPartCount() != 5 {
+				t.Errorf("GetPartCount got %d, want %d", decrypted.GetPartCount(), 5)
+			}
+
+			header, err := decrypted.GetHeader()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			alg, _ := header.GetAlgorithm()
+			if alg != e.Alg() {
+				t.Errorf("GetAlgorithm got %s, want %s", alg, e.Alg())
+			}
+			enc, _ := header.GetEncryption()
+			if enc != e.Enc() {
+				t.Errorf("GetEncryption got %s, want %s", enc, e.Enc())
+			}
+			typ, _ := header.GetType()
+			if typ != "JWT" {
+				t.Errorf("GetType got %s, want %s", typ, "JWT")
+			}
+
+			claims2, err := decrypted.GetClaims()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims2["aud"].(string) != claims["aud"] {
+				t.Errorf("GetClaims aud got %s, want %s", claims2["aud"].(string), claims["aud"])
+			}
+			if claims2["sub"].(string) != claims["sub"] {
+				t.Errorf("GetClaims sub got %s, want %s", claims2["sub"].(string), claims["sub"])
+			}
+		})
+	}
+}
+
+func Test_JWE_RSAOAEP(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := &privateKey.PublicKey
+
+	claims := map[string]string{
+		"aud": "example.com",
+		"sub": "foo",
+	}
+
+	for _, km := range []*KeyRSAOAEP{KeyManagementRSAOAEP, KeyManagementRSAOAEP256} {
+		t.Run(km.Alg(), func(t *testing.T) {
+			e := NewJWE[*rsa.PublicKey, *rsa.PrivateKey](km, ContentEncryptionA256GCM, JWTEncoder).New()
+
+			tokenString, err := e.Encrypt(claims, publicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decrypted, err := e.Decrypt(tokenString, privateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims2, err := decrypted.GetClaims()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims2["sub"].(string) != claims["sub"] {
+				t.Errorf("GetClaims sub got %s, want %s", claims2["sub"].(string), claims["sub"])
+			}
+
+			otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = e.Decrypt(tokenString, otherKey)
+			if !errors.Is(err, ErrJWEDecryptFail) {
+				t.Errorf("Decrypt got %v, want %v", err, ErrJWEDecryptFail)
+			}
+		})
+	}
+}
+
+func Test_JWE_EncryptWithHeader(t *testing.T) {
+	key := []byte("1234567890123456")
+
+	header := map[string]string{
+		"typ": "JWT",
+		"kid": "key-1",
+		"alg": "RSA-OAEP",
+	}
+	claims := map[string]string{
+		"sub": "foo",
+	}
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128GCM, JWTEncoder).New()
+
+	tokenString, err := e.EncryptWithHeader(header, claims, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := e.Decrypt(tokenString, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header2, err := decrypted.GetHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid, _ := header2.GetKeyID()
+	if kid != "key-1" {
+		t.Errorf("GetKeyID got %s, want %s", kid, "key-1")
+	}
+	alg, _ := header2.GetAlgorithm()
+	if alg != "A128KW" {
+		t.Errorf("GetAlgorithm got %s, want %s", alg, "A128KW")
+	}
+}
+
+func Test_JWE_Decrypt_Error(t *testing.T) {
+	key := []byte("1234567890123456")
+	claims := map[string]string{
+		"sub": "foo",
+	}
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128GCM, JWTEncoder).New()
+
+	tokenString, err := e.Encrypt(claims, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		_, err = e.Decrypt("eyJ0eXAiOiJKV1QiLCJhbGciOiJFUzI1NiJ9.eyJhdWQiOiJleGFtcGxlLmNvbSJ9.dGVzdA", key)
+		if !errors.Is(err, ErrJWETokenInvalid) {
+			t.Errorf("Decrypt got %v, want %v", err, ErrJWETokenInvalid)
+		}
+	}
+
+	{
+		e2 := NewJWE[[]byte, []byte](KeyManagementDir, ContentEncryptionA128GCM, JWTEncoder).New()
+		_, err = e2.Decrypt(tokenString, key)
+		if !errors.Is(err, ErrJWTAlgoInvalid) {
+			t.Errorf("Decrypt got %v, want %v", err, ErrJWTAlgoInvalid)
+		}
+	}
+
+	{
+		e2 := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA256GCM, JWTEncoder).New()
+		_, err = e2.Decrypt(tokenString, key)
+		if !errors.Is(err, ErrJWEEncInvalid) {
+			t.Errorf("Decrypt got %v, want %v", err, ErrJWEEncInvalid)
+		}
+	}
+
+	{
+		headers := []struct {
+			header map[string]any
+			want   error
+		}{
+			{map[string]any{"crit": []string{"exp"}, "exp": 1}, ErrJWECritUnsupported},
+			{map[string]any{"zip": "DEF"}, ErrJWEZipUnsupported},
+		}
+
+		for _, h := range headers {
+			tokenString2, err := e.EncryptWithHeader(h.header, claims, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = e.Decrypt(tokenString2, key)
+			if !errors.Is(err, h.want) {
+				t.Errorf("Decrypt got %v, want %v", err, h.want)
+			}
+		}
+	}
+
+	{
+		// tamper the ciphertext
+		tampered := []byte(tokenString)
+		idx := len(tampered) - 30
+		if tampered[idx] == 'A' {
+			tampered[idx] = 'B'
+		} else {
+			tampered[idx] = 'A'
+		}
+
+		_, err = e.Decrypt(string(tampered), key)
+		if err == nil {
+			t.Error("Decrypt should return error")
+		}
+	}
+
+	{
+		_, err = e.Decrypt(tokenString+"a", key)
+		if err == nil {
+			t.Error("Decrypt should return error")
+		}
+	}
+}
+
+func Test_NewJWE_Panic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrJWEContentEncryptionInvalid {
+			t.Errorf("NewJWE panic got %v, want %v", r, ErrJWEContentEncryptionInvalid)
+		}
+	}()
+
+	NewJWE[[]byte, []byte](KeyManagementDir, nil, JWTEncoder)
+}
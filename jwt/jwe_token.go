@@ -0,0 +1,220 @@
+package jwt
+
+import (
+	"strings"
+)
+
+// EncryptedToken represents a JWE Token with compact serialization.
+type EncryptedToken struct {
+	raw          string // full token string
+	protected    string // base64url encoded header, used as additional authenticated data
+	header       []byte
+	encryptedKey []byte
+	iv           []byte
+	ciphertext   []byte
+	tag          []byte
+	claims       []byte // decrypted plaintext
+	encoder      IEncoder
+}
+
+func NewEncryptedToken(encoder IEncoder) *EncryptedToken {
+	return &EncryptedToken{
+		encoder: encoder,
+	}
+}
+
+// Set header raw
+func (t *EncryptedToken) WithHeader(header []byte) {
+	t.header = header
+	t.protected = ""
+}
+
+// Set header with json encode
+func (t *EncryptedToken) SetHeader(header any) error {
+	encoded, err := t.encoder.JSONEncode(header)
+	if err != nil {
+		return err
+	}
+
+	t.WithHeader(encoded)
+	return nil
+}
+
+// Set claims raw
+func (t *EncryptedToken) WithClaims(claims []byte) {
+	t.claims = claims
+}
+
+// Set claims with json encode
+func (t *EncryptedToken) SetClaims(claims any) error {
+	encoded, err := t.encoder.JSONEncode(claims)
+	if err != nil {
+		return err
+	}
+
+	t.claims = encoded
+	return nil
+}
+
+// Set encrypted key raw
+func (t *EncryptedToken) WithEncryptedKey(encryptedKey []byte) {
+	t.encryptedKey = encryptedKey
+}
+
+// Set initialization vector raw
+func (t *EncryptedToken) WithIV(iv []byte) {
+	t.iv = iv
+}
+
+// Set ciphertext raw
+func (t *EncryptedToken) WithCiphertext(ciphertext []byte) {
+	t.ciphertext = ciphertext
+}
+
+// Set authentication tag raw
+func (t *EncryptedToken) WithTag(tag []byte) {
+	t.tag = tag
+}
+
+// EncryptedString creates and returns a complete JWE compact serialization.
+func (t *EncryptedToken) EncryptedString() (string, error) {
+	protected, err := t.protectedHeader()
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{protected}
+	for _, part := range [][]byte{t.encryptedKey, t.iv, t.ciphertext, t.tag} {
+		encoded, err := t.encoder.Base64URLEncode(part)
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, encoded)
+	}
+
+	return strings.Join(parts, tokenDelimiter), nil
+}
+
+// Parse token string. JWE compact serialization must have five parts.
+func (t *EncryptedToken) Parse(tokenString string) error {
+	t.raw = tokenString
+	t.protected = ""
+	t.header = []byte{}
+	t.encryptedKey = []byte{}
+	t.iv = []byte{}
+	t.ciphertext = []byte{}
+	t.tag = []byte{}
+	t.claims = []byte{}
+
+	list := strings.Split(tokenString, tokenDelimiter)
+	if len(list) != 5 {
+		return ErrJWETokenInvalid
+	}
+
+	decoded := make([][]byte, len(list))
+	for i, part := range list {
+		data, err := t.encoder.Base64URLDecode(part)
+		if err != nil {
+			return NewError("", ErrJWETokenInvalid, err)
+		}
+
+		decoded[i] = data
+	}
+
+	t.protected = list[0]
+	t.header = decoded[0]
+	t.encryptedKey = decoded[1]
+	t.iv = decoded[2]
+	t.ciphertext = decoded[3]
+	t.tag = decoded[4]
+
+	return nil
+}
+
+// return the base64url encoded protected header
+func (t *EncryptedToken) protectedHeader() (string, error) {
+	if t.protected != "" {
+		return t.protected, nil
+	}
+
+	protected, err := t.encoder.Base64URLEncode(t.header)
+	if err != nil {
+		return "", err
+	}
+
+	t.protected = protected
+	return protected, nil
+}
+
+// return token raw
+func (t *EncryptedToken) GetRaw() string {
+	return t.raw
+}
+
+// return token string part count
+func (t *EncryptedToken) GetPartCount() int {
+	return len(strings.Split(t.raw, tokenDelimiter))
+}
+
+// return token MapHeaders struct
+func (t *EncryptedToken) GetHeader() (MapHeaders, error) {
+	var dst MapHeaders
+	err := t.encoder.JSONDecode(t.header, &dst)
+	if err != nil {
+		return map[string]any{}, err
+	}
+
+	return dst, nil
+}
+
+// return token header with custom type
+func (t *EncryptedToken) GetHeadersT(dst any) error {
+	return t.encoder.JSONDecode(t.header, dst)
+}
+
+// return token header raw
+func (t *EncryptedToken) GetHeaderRaw() []byte {
+	return t.header
+}
+
+// return token encrypted key
+func (t *EncryptedToken) GetEncryptedKey() []byte {
+	return t.encryptedKey
+}
+
+// return token initialization vector
+func (t *EncryptedToken) GetIV() []byte {
+	return t.iv
+}
+
+// return token ciphertext
+func (t *EncryptedToken) GetCiphertext() []byte {
+	return t.ciphertext
+}
+
+// return token authentication tag
+func (t *EncryptedToken) GetTag() []byte {
+	return t.tag
+}
+
+// return decrypted token claims map
+func (t *EncryptedToken) GetClaims() (MapClaims, error) {
+	var dst MapClaims
+	err := t.encoder.JSONDecode(t.claims, &dst)
+	if err != nil {
+		return map[string]any{}, err
+	}
+
+	return dst, nil
+}
+
+// return decrypted token claims with custom type
+func (t *EncryptedToken) GetClaimsT(dst any) error {
+	return t.encoder.JSONDecode(t.claims, dst)
+}
+
+// return decrypted token claims raw
+func (t *EncryptedToken) GetClaimsRaw() []byte {
+	return t.claims
+}
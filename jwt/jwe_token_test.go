@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_EncryptedToken(t *testing.T) {
+	var header = RegisteredHeaders{
+		Algorithm:  "dir",
+		Encryption: "A128GCM",
+	}
+
+	var check = "eyJhbGciOiJkaXIiLCJlbmMiOiJBMTI4R0NNIn0..aXYtZGF0YQ.Y2lwaGVydGV4dA.dGFn"
+
+	var token = NewEncryptedToken(JWTEncoder)
+	token.SetHeader(header)
+	token.WithIV([]byte("iv-data"))
+	token.WithCiphertext([]byte("ciphertext"))
+	token.WithTag([]byte("tag"))
+
+	res, err := token.EncryptedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != check {
+		t.Errorf("EncryptedString got %s, want %s", res, check)
+	}
+
+	// ====================
+
+	var token2 = NewEncryptedToken(JWTEncoder)
+	if err := token2.Parse(check); err != nil {
+		t.Fatal(err)
+	}
+
+	if token2.GetRaw() != check {
+		t.Errorf("GetRaw got %s, want %s", token2.GetRaw(), check)
+	}
+	if token2.GetPartCount() != 5 {
+		t.Errorf("GetPartCount got %d, want %d", token2.GetPartCount(), 5)
+	}
+
+	header2, err := token2.GetHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, _ := header2.GetEncryption()
+	if enc != "A128GCM" {
+		t.Errorf("GetHeader enc got %s, want %s", enc, "A128GCM")
+	}
+
+	var header3 RegisteredHeaders
+	if err := token2.GetHeadersT(&header3); err != nil {
+		t.Fatal(err)
+	}
+	if header3.Algorithm != "dir" {
+		t.Errorf("GetHeadersT alg got %s, want %s", header3.Algorithm, "dir")
+	}
+
+	if len(token2.GetEncryptedKey()) != 0 {
+		t.Errorf("GetEncryptedKey got %x, want empty", token2.GetEncryptedKey())
+	}
+	if !bytes.Equal(token2.GetIV(), []byte("iv-data")) {
+		t.Errorf("GetIV got %s, want %s", token2.GetIV(), "iv-data")
+	}
+	if !bytes.Equal(token2.GetCiphertext(), []byte("ciphertext")) {
+		t.Errorf("GetCiphertext got %s, want %s", token2.GetCiphertext(), "ciphertext")
+	}
+	if !bytes.Equal(token2.GetTag(), []byte("tag")) {
+		t.Errorf("GetTag got %s, want %s", token2.GetTag(), "tag")
+	}
+}
+
+func Test_EncryptedToken_Parse_Error(t *testing.T) {
+	var token = NewEncryptedToken(JWTEncoder)
+
+	err := token.Parse("eyJhbGciOiJkaXIiLCJlbmMiOiJBMTI4R0NNIn0..aXYtZGF0YQ.Y2lwaGVydGV4dA")
+	if !errors.Is(err, ErrJWETokenInvalid) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWETokenInvalid)
+	}
+
+	err = token.Parse("eyJhbGciOiJkaXIiLCJlbmMiOiJBMTI4R0NNIn0..aXYtZGF0YQ.Y2lwaGVydGV4dA.dGFn*")
+	if !errors.Is(err, ErrJWETokenInvalid) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWETokenInvalid)
+	}
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rsa"
@@ -122,6 +123,15 @@ type IEncoder interface {
 type JWT[S any, V any] struct {
 	signer  ISigner[S, V]
 	encoder IEncoder
+
+	// hash for the `kid` header thumbprint, 0 is not set `kid`
+	kidHash crypto.Hash
+
+	// the clock for the builder and parser, SystemClock is used when nil
+	clock Clock
+
+	// the default claims policy for the builder
+	builderPolicy BuilderPolicy
 }
 
 func NewJWT[S any, V any](signer ISigner[S, V], encoder IEncoder) JWT[S, V] {
@@ -143,6 +153,10 @@ func (jwt JWT[S, V]) New() *JWT[S, V] {
 	return &JWT[S, V]{
 		signer:  jwt.signer,
 		encoder: jwt.encoder,
+		kidHash: jwt.kidHash,
+		clock:   jwt.clock,
+
+		builderPolicy: jwt.builderPolicy,
 	}
 }
 
@@ -152,11 +166,38 @@ func (jwt *JWT[S, V]) WithEncoder(encoder IEncoder) *JWT[S, V] {
 	return jwt
 }
 
+// with the `kid` header set from the signing key JWK thumbprint
+func (jwt *JWT[S, V]) WithKeyIDThumbprint(hash crypto.Hash) *JWT[S, V] {
+	jwt.kidHash = hash
+	return jwt
+}
+
+// with the clock for the builder and the parse time claims
+func (jwt *JWT[S, V]) WithClock(clock Clock) *JWT[S, V] {
+	jwt.clock = clock
+	return jwt
+}
+
+// with the default claims policy used by Build
+func (jwt *JWT[S, V]) WithBuilderPolicy(policy BuilderPolicy) *JWT[S, V] {
+	jwt.builderPolicy = policy
+	return jwt
+}
+
 // return a JWT signer
 func (jwt *JWT[S, V]) GetSigner() ISigner[S, V] {
 	return jwt.signer
 }
 
+// return the JWT clock, SystemClock is returned when it is not set
+func (jwt *JWT[S, V]) GetClock() Clock {
+	if jwt.clock == nil {
+		return SystemClock
+	}
+
+	return jwt.clock
+}
+
 // Signer algo name.
 func (jwt *JWT[S, V]) Alg() string {
 	return jwt.signer.Alg()
@@ -179,6 +220,19 @@ func (jwt *JWT[S, V]) Sign(claims any, signKey S) (string, error) {
 
 // SignWithHeader implements token signing for the Signer.
 func (jwt *JWT[S, V]) SignWithHeader(header any, claims any, signKey S) (string, error) {
+	if jwt.kidHash != 0 {
+		headers, err := toMapHeaders(jwt.encoder, header)
+		if err != nil {
+			return "", err
+		}
+
+		if err = setThumbprintKeyID(headers, signKey, jwt.kidHash); err != nil {
+			return "", err
+		}
+
+		header = headers
+	}
+
 	t := NewToken(jwt.encoder)
 	t.SetHeader(header)
 	t.SetClaims(claims)
@@ -199,41 +253,47 @@ func (jwt *JWT[S, V]) SignWithHeader(header any, claims any, signKey S) (string,
 }
 
 // Parse parses the signature and returns the parsed token.
-func (jwt *JWT[S, V]) Parse(tokenString string, verifyKey V) (*Token, error) {
-	t := NewToken(jwt.encoder)
-	t.Parse(tokenString)
-
-	if t.GetPartCount() < 2 {
-		return nil, ErrJWTTokenInvalid
+// The claims are validated with the parser option, the option Encoder
+// and ValidMethods are not used, the JWT encoder and algo are used.
+// The returned errors are *ValidationError.
+func (jwt *JWT[S, V]) Parse(tokenString string, verifyKey V, opt ...ParserOption) (*Token, error) {
+	var parserOpt ParserOption
+	if len(opt) > 0 {
+		parserOpt = opt[0]
 	}
 
-	header, err := t.GetHeader()
-	if err != nil {
-		return nil, err
+	parserOpt.Encoder = jwt.encoder
+	parserOpt.ValidMethods = nil
+
+	if parserOpt.Clock == nil {
+		parserOpt.Clock = jwt.clock
 	}
 
-	typ, err := header.GetType()
+	t, err := parseToken(tokenString, parserOpt)
 	if err != nil {
 		return nil, err
 	}
-	if len(typ) > 0 && typ != "JWT" {
-		return nil, ErrJWTTypeInvalid
-	}
 
-	alg, err := header.GetAlgorithm()
+	alg, err := checkTokenHeader(t, parserOpt)
 	if err != nil {
 		return nil, err
 	}
+
 	if alg != jwt.signer.Alg() {
-		return nil, ErrJWTAlgoInvalid
+		return nil, newValidationError(ErrJWTAlgoInvalid, ErrJWTTokenUnverifiable)
 	}
 
 	signature := t.GetSignature()
 	signingString := t.GetMsg()
 
-	ok, _ := jwt.signer.Verify([]byte(signingString), signature, verifyKey)
-	if !ok {
-		return nil, ErrJWTVerifyFail
+	ok, err := jwt.signer.Verify([]byte(signingString), signature, verifyKey)
+	if !ok || err != nil {
+		return nil, newValidationError(ErrJWTVerifyFail, ErrJWTTokenSignatureInvalid).
+			withCauses(err)
+	}
+
+	if err := validateTokenClaims(t, parserOpt); err != nil {
+		return nil, err
 	}
 
 	return t, nil
@@ -241,7 +301,10 @@ func (jwt *JWT[S, V]) Parse(tokenString string, verifyKey V) (*Token, error) {
 
 // return a new *Builder.
 func (jwt *JWT[S, V]) Build() *Builder[S] {
-	return NewBuilder[S](jwt.signer, jwt.encoder)
+	return NewBuilder[S](jwt.signer, jwt.encoder).
+		WithKeyIDThumbprint(jwt.kidHash).
+		WithClock(jwt.clock).
+		WithPolicy(jwt.builderPolicy)
 }
 
 // get token header from token string
@@ -254,7 +317,9 @@ func GetTokenHeader(tokenString string, encoder ...IEncoder) (MapHeaders, error)
 	}
 
 	var t = NewToken(useEncoder)
-	t.Parse(tokenString)
+	if err := t.Parse(tokenString); err != nil {
+		return nil, err
+	}
 
 	return t.GetHeader()
 }
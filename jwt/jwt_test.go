@@ -6,7 +6,9 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"testing"
+	"time"
 )
 
 func Test_SigningMethodHMD5(t *testing.T) {
@@ -360,7 +362,9 @@ func Test_SigningMethodHS256_Check(t *testing.T) {
 	}
 
 	p := SigningMethodHS256.New()
-	parsed, err := p.Parse(tokenStr, keyBytes)
+	parsed, err := p.Parse(tokenStr, keyBytes, ParserOption{
+		Clock: NewFrozenClock(time.Unix(1300819300, 0)),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -398,7 +402,9 @@ func Test_SigningMethodHS384_Check(t *testing.T) {
 	}
 
 	p := SigningMethodHS384.New()
-	parsed, err := p.Parse(tokenStr, keyBytes)
+	parsed, err := p.Parse(tokenStr, keyBytes, ParserOption{
+		Clock: NewFrozenClock(time.Unix(1300819300, 0)),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -436,7 +442,9 @@ func Test_SigningMethodHS512_Check(t *testing.T) {
 	}
 
 	p := SigningMethodHS512.New()
-	parsed, err := p.Parse(tokenStr, keyBytes)
+	parsed, err := p.Parse(tokenStr, keyBytes, ParserOption{
+		Clock: NewFrozenClock(time.Unix(1300819300, 0)),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -479,6 +487,10 @@ func Test_GetTokenHeader(t *testing.T) {
 		t.Errorf("GetTokenHeader Alg got %s, want %s", alg, "ES256")
 	}
 
+	_, err = GetTokenHeader("e$.e30.e30")
+	if !errors.Is(err, ErrJWTTokenMalformed) {
+		t.Errorf("GetTokenHeader got %v, want %v", err, ErrJWTTokenMalformed)
+	}
 }
 
 func Test_SigningMethodBLAKE2B(t *testing.T) {
@@ -550,7 +562,9 @@ func Test_SigningMethodBLAKE2B_Check(t *testing.T) {
 	}
 
 	p := SigningMethodBLAKE2B.New()
-	parsed, err := p.Parse(tokenStr, keyBytes)
+	parsed, err := p.Parse(tokenStr, keyBytes, ParserOption{
+		Clock: NewFrozenClock(time.Unix(1300819300, 0)),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2103,6 +2117,10 @@ func Test_GetTokenHeader_WithEncoder(t *testing.T) {
 		t.Errorf("GetTokenHeader Alg got %s, want %s", alg, "ES256")
 	}
 
+	_, err = GetTokenHeader("e$.e30.e30")
+	if !errors.Is(err, ErrJWTTokenMalformed) {
+		t.Errorf("GetTokenHeader got %v, want %v", err, ErrJWTTokenMalformed)
+	}
 }
 
 func Test_NewJWT_Error(t *testing.T) {
@@ -2583,7 +2601,9 @@ func Test_SigningMethodPS256_Check2(t *testing.T) {
 	}
 
 	p := SigningMethodPS256.New()
-	parsed, err := p.Parse(tokenStr, publicKey)
+	parsed, err := p.Parse(tokenStr, publicKey, ParserOption{
+		Clock: NewFrozenClock(time.Unix(1300819300, 0)),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
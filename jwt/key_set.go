@@ -0,0 +1,195 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/deatil/go-jwt/jwk"
+)
+
+var (
+	ErrKeySetKeyNotFound = errors.New("go-jwt: KeySet key not found")
+)
+
+// key set interface, used by ParseWithKeySet
+type IKeySet interface {
+	// LookupKeys returns the keys with the `kid`, or all keys when `kid` is empty.
+	LookupKeys(kid string) ([]*KeySetKey, error)
+}
+
+// KeySetKey is a verification key in a key set
+type KeySetKey struct {
+	kid  string
+	algs []string
+	key  any
+
+	// return the verify function for the alg, false when the key
+	// type is not compatible with the signing method
+	verifier func(alg string) (func(msg []byte, signature []byte) (bool, error), bool)
+}
+
+// NewKeySetKey returns a key set key. The key is only used with
+// the algs when algs not empty, and the "none" alg is only used
+// when it is in the algs.
+func NewKeySetKey[V any](kid string, key V, algs ...string) *KeySetKey {
+	return &KeySetKey{
+		kid:  kid,
+		algs: algs,
+		key:  key,
+		verifier: func(alg string) (func(msg []byte, signature []byte) (bool, error), bool) {
+			signer, ok := GetSigningMethod(alg).(IVerifying[V])
+			if !ok {
+				return nil, false
+			}
+
+			return func(msg []byte, signature []byte) (bool, error) {
+				return signer.Verify(msg, signature, key)
+			}, true
+		},
+	}
+}
+
+// return the key id
+func (k *KeySetKey) KeyID() string {
+	return k.kid
+}
+
+// return the allowed algs
+func (k *KeySetKey) Algs() []string {
+	return k.algs
+}
+
+// return the key
+func (k *KeySetKey) Key() any {
+	return k.key
+}
+
+// Compatible returns true when the key can verify the alg.
+func (k *KeySetKey) Compatible(alg string) bool {
+	if len(k.algs) > 0 {
+		if !slices.Contains(k.algs, alg) {
+			return false
+		}
+	} else if alg == SigningNone.Alg() {
+		return false
+	}
+
+	_, ok := k.verifier(alg)
+	return ok
+}
+
+// Verify verifies the signature with the alg signing method.
+func (k *KeySetKey) Verify(alg string, msg []byte, signature []byte) (bool, error) {
+	if !k.Compatible(alg) {
+		return false, ErrJWTMethodInvalid
+	}
+
+	verify, _ := k.verifier(alg)
+	return verify(msg, signature)
+}
+
+// KeySet holds many verification keys with mixed algorithms
+type KeySet struct {
+	keys []*KeySetKey
+	lock sync.RWMutex
+}
+
+func NewKeySet(keys ...*KeySetKey) *KeySet {
+	return &KeySet{
+		keys: keys,
+	}
+}
+
+// NewKeySetFromJWKSet returns a key set with the JWK Set public keys.
+// Keys for encryption are ignored, and the JWK `alg` limits the key algs.
+func NewKeySetFromJWKSet(set *jwk.Set) *KeySet {
+	ks := NewKeySet()
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if len(k.KeyOps) > 0 && !slices.Contains(k.KeyOps, "verify") {
+			continue
+		}
+
+		var algs []string
+		if k.Algorithm != "" {
+			algs = []string{k.Algorithm}
+		}
+
+		switch key := k.Key.(type) {
+		case *rsa.PrivateKey:
+			ks.Add(NewKeySetKey(k.KeyID, &key.PublicKey, algs...))
+		case *rsa.PublicKey:
+			ks.Add(NewKeySetKey(k.KeyID, key, algs...))
+		case *ecdsa.PrivateKey:
+			ks.Add(NewKeySetKey(k.KeyID, &key.PublicKey, algs...))
+		case *ecdsa.PublicKey:
+			ks.Add(NewKeySetKey(k.KeyID, key, algs...))
+		case ed25519.PrivateKey:
+			ks.Add(NewKeySetKey(k.KeyID, key.Public().(ed25519.PublicKey), algs...))
+		case ed25519.PublicKey:
+			ks.Add(NewKeySetKey(k.KeyID, key, algs...))
+		case []byte:
+			ks.Add(NewKeySetKey(k.KeyID, key, algs...))
+		}
+	}
+
+	return ks
+}
+
+// Add adds keys to the key set
+func (ks *KeySet) Add(keys ...*KeySetKey) *KeySet {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.keys = append(ks.keys, keys...)
+	return ks
+}
+
+// Remove removes the keys with the `kid`
+func (ks *KeySet) Remove(kid string) *KeySet {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.keys = slices.DeleteFunc(ks.keys, func(k *KeySetKey) bool {
+		return k.kid == kid
+	})
+	return ks
+}
+
+// return the keys count
+func (ks *KeySet) Len() int {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
+	return len(ks.keys)
+}
+
+// LookupKeys returns the keys with the `kid`, or all keys when `kid` is empty.
+func (ks *KeySet) LookupKeys(kid string) ([]*KeySetKey, error) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
+	if kid == "" {
+		return slices.Clone(ks.keys), nil
+	}
+
+	var keys []*KeySetKey
+	for _, k := range ks.keys {
+		if k.kid == kid {
+			keys = append(keys, k)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrKeySetKeyNotFound
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,252 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/deatil/go-jwt/jwk"
+)
+
+func Test_ParseWithKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmacKey := []byte("test-key")
+
+	ks := NewKeySet(
+		NewKeySetKey("rsa", &rsaKey.PublicKey),
+		NewKeySetKey("ec", &ecKey.PublicKey, "ES256"),
+	).Add(NewKeySetKey("hmac", hmacKey, "HS256", "HS384"))
+
+	if ks.Len() != 3 {
+		t.Errorf("Len got %d, want %d", ks.Len(), 3)
+	}
+
+	claims := map[string]string{
+		"sub": "foo",
+	}
+
+	{
+		tokenString, err := SigningMethodRS256.SignWithHeader(RegisteredHeaders{
+			Type:      "JWT",
+			Algorithm: "RS256",
+			KeyID:     "rsa",
+		}, claims, rsaKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := ParseWithKeySet(tokenString, ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims2, _ := parsed.GetClaims()
+		sub, _ := claims2.GetSubject()
+		if sub != "foo" {
+			t.Errorf("GetSubject got %s, want %s", sub, "foo")
+		}
+	}
+
+	{
+		tokenString, err := SigningMethodES256.SignWithHeader(RegisteredHeaders{
+			Type:      "JWT",
+			Algorithm: "ES256",
+			KeyID:     "ec",
+		}, claims, ecKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParseWithKeySet(tokenString, ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tokenString, err := SigningMethodHS384.Sign(claims, hmacKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// no `kid`, try every compatible key
+		_, err = ParseWithKeySet(tokenString, ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		// HS512 is not in the HMAC key algs
+		tokenString, err := SigningMethodHS512.Sign(claims, hmacKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParseWithKeySet(tokenString, ks)
+		if !errors.Is(err, ErrKeySetKeyNotFound) {
+			t.Errorf("ParseWithKeySet got %v, want %v", err, ErrKeySetKeyNotFound)
+		}
+	}
+
+	{
+		tokenString, err := SigningMethodRS256.SignWithHeader(RegisteredHeaders{
+			Type:      "JWT",
+			Algorithm: "RS256",
+			KeyID:     "unknown",
+		}, claims, rsaKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParseWithKeySet(tokenString, ks)
+		if !errors.Is(err, ErrKeySetKeyNotFound) {
+			t.Errorf("ParseWithKeySet got %v, want %v", err, ErrKeySetKeyNotFound)
+		}
+	}
+
+	{
+		// RSA key with the `ec` kid
+		tokenString, err := SigningMethodRS256.SignWithHeader(RegisteredHeaders{
+			Type:      "JWT",
+			Algorithm: "RS256",
+			KeyID:     "ec",
+		}, claims, rsaKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParseWithKeySet(tokenString, ks)
+		if !errors.Is(err, ErrKeySetKeyNotFound) {
+			t.Errorf("ParseWithKeySet got %v, want %v", err, ErrKeySetKeyNotFound)
+		}
+	}
+
+	{
+		tokenString, err := SigningMethodNone.Sign(claims, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParseWithKeySet(tokenString, ks)
+		if !errors.Is(err, ErrKeySetKeyNotFound) {
+			t.Errorf("ParseWithKeySet none got %v, want %v", err, ErrKeySetKeyNotFound)
+		}
+	}
+
+	ks.Remove("hmac")
+	if ks.Len() != 2 {
+		t.Errorf("Len got %d, want %d", ks.Len(), 2)
+	}
+}
+
+func Test_ParseWithKeySet_Rotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks := NewKeySet(
+		NewKeySetKey("", &oldKey.PublicKey, "RS256"),
+		NewKeySetKey("", &newKey.PublicKey, "RS256"),
+	)
+
+	claims := map[string]string{
+		"sub": "foo",
+	}
+
+	for _, key := range []*rsa.PrivateKey{oldKey, newKey} {
+		tokenString, err := SigningMethodRS256.Sign(claims, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParseWithKeySet(tokenString, ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tokenString, err := SigningMethodRS256.Sign(claims, otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseWithKeySet(tokenString, ks)
+	if !errors.Is(err, ErrJWTVerifyFail) {
+		t.Errorf("ParseWithKeySet got %v, want %v", err, ErrJWTVerifyFail)
+	}
+
+	_, err = ParseWithKeySet(tokenString, ks, ParserOption{
+		Encoder:      JWTEncoder,
+		ValidMethods: []string{"ES256"},
+	})
+	if !errors.Is(err, ErrJWTTokenSignatureInvalid) {
+		t.Errorf("ParseWithKeySet got %v, want %v", err, ErrJWTTokenSignatureInvalid)
+	}
+}
+
+func Test_NewKeySetFromJWKSet(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigKey, _ := jwk.New(privateKey)
+	sigKey.KeyID = "ed"
+	sigKey.Use = "sig"
+
+	encKey, _ := jwk.New(publicKey)
+	encKey.KeyID = "enc"
+	encKey.Use = "enc"
+
+	ks := NewKeySetFromJWKSet(jwk.NewSet(sigKey, encKey))
+	if ks.Len() != 1 {
+		t.Errorf("Len got %d, want %d", ks.Len(), 1)
+	}
+
+	keys, err := ks.LookupKeys("ed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := keys[0].Key().(ed25519.PublicKey); !ok {
+		t.Error("Key should be ed25519.PublicKey")
+	}
+
+	tokenString, err := SigningMethodEdDSA.SignWithHeader(RegisteredHeaders{
+		Type:      "JWT",
+		Algorithm: "EdDSA",
+		KeyID:     "ed",
+	}, map[string]string{"sub": "foo"}, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseWithKeySet(tokenString, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
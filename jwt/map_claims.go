@@ -216,10 +216,19 @@ func (m MapClaims) parseNumericDate(key string) (*NumericDate, error) {
 		if exp == 0 {
 			return nil, nil
 		}
+		if math.IsNaN(exp) || math.IsInf(exp, 0) {
+			return nil, NewError(fmt.Sprintf("%s is not finite", key), ErrJWTTokenInvalidClaims)
+		}
 
 		return newNumericDateFromSeconds(exp), nil
 	case json.Number:
-		v, _ := exp.Float64()
+		v, err := exp.Float64()
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, NewError(fmt.Sprintf("%s is not finite", key), ErrJWTTokenInvalidClaims)
+		}
+		if err != nil {
+			return nil, NewError(fmt.Sprintf("%s is invalid", key), ErrJWTInvalidType)
+		}
 
 		return newNumericDateFromSeconds(v), nil
 	}
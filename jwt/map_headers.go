@@ -24,6 +24,10 @@ func (m MapHeaders) GetContentType() (string, error) {
 	return m.parseString("cty")
 }
 
+func (m MapHeaders) GetEncryption() (string, error) {
+	return m.parseString("enc")
+}
+
 func (m MapHeaders) GetString(name string) (string, error) {
 	return m.parseString(name)
 }
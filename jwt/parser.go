@@ -1,7 +1,10 @@
 package jwt
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 )
 
 // jwt ParserOption for Parse function
@@ -11,6 +14,33 @@ type ParserOption struct {
 
 	// jwt valid methods
 	ValidMethods []string
+
+	// expected `iss` claim, not checked when empty
+	Issuer string
+
+	// expected `aud` claim, the token must be permitted for one of them
+	Audience []string
+
+	// expected `sub` claim, not checked when empty
+	Subject string
+
+	// leeway for the `exp`, `nbf` and `iat` claims
+	Leeway time.Duration
+
+	// claims the token must have
+	RequiredClaims []string
+
+	// the clock for the time claims, SystemClock is used when nil
+	Clock Clock
+
+	// skip the claims validation, the revocation and replay are still checked
+	SkipClaimsValidation bool
+
+	// the revocation store checked after the signature is verified
+	Revocation RevocationStore
+
+	// the replay cache for the single-use tokens, checked at last
+	ReplayCache ReplayCache
 }
 
 // default ParserOption
@@ -19,51 +49,238 @@ var JWTParserOption = ParserOption{
 }
 
 // Parse parses the signature and returns the parsed token.
+// The returned errors are *ValidationError.
 func Parse[V any](tokenString string, keyFunc func(t *Token) (key V, err error), opt ...ParserOption) (*Token, error) {
-	var parserOpt ParserOption
-	if len(opt) > 0 {
-		parserOpt = opt[0]
-	} else {
-		parserOpt = ParserOption{
-			Encoder: JWTEncoder,
+	parserOpt := getParserOption(opt)
+
+	t, err := parseAndVerify(tokenString, keyFunc, parserOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTokenClaims(t, parserOpt); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ParseWithClaims parses the signature, decodes the claims to the C type
+// and validates the claims with the Claims interface getters.
+// The C type can be a struct embedding RegisteredClaims, or a pointer to it.
+// The returned errors are *ValidationError.
+func ParseWithClaims[C Claims, V any](tokenString string, keyFunc func(t *Token) (key V, err error), opt ...ParserOption) (C, *Token, error) {
+	var claims C
+
+	parserOpt := getParserOption(opt)
+
+	t, err := parseAndVerify(tokenString, keyFunc, parserOpt)
+	if err != nil {
+		return claims, nil, err
+	}
+
+	if err := t.GetClaimsT(&claims); err != nil {
+		return claims, nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	// the `null` claims makes a nil pointer
+	if v := reflect.ValueOf(claims); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return claims, nil, newValidationError(ErrJWTTokenInvalid, ErrJWTTokenMalformed)
+	}
+
+	if !parserOpt.checkClaims() {
+		return claims, t, nil
+	}
+
+	raw, err := t.GetClaims()
+	if err != nil {
+		return claims, nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	if !parserOpt.SkipClaimsValidation {
+		if err := validateClaims(claims, raw, parserOpt); err != nil {
+			return claims, nil, newClaimsValidationError(err)
 		}
 	}
 
+	if err := checkRevocation(claims, raw, parserOpt); err != nil {
+		return claims, nil, err
+	}
+
+	if err := checkReplay(claims, raw, parserOpt); err != nil {
+		return claims, nil, err
+	}
+
+	return claims, t, nil
+}
+
+// parse the token and verify the signature with the keyFunc key
+func parseAndVerify[V any](tokenString string, keyFunc func(t *Token) (key V, err error), parserOpt ParserOption) (*Token, error) {
+	t, err := parseToken(tokenString, parserOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keyFunc(t)
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenUnverifiable)
+	}
+
+	alg, err := checkTokenHeader(t, parserOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	signingMethod := GetSigningMethod(alg)
+	if signingMethod == nil {
+		return nil, newValidationError(ErrJWTMethodExists, ErrJWTTokenUnverifiable)
+	}
+
+	signer, ok := signingMethod.(IVerifying[V])
+	if !ok {
+		return nil, newValidationError(ErrJWTMethodInvalid, ErrJWTTokenUnverifiable)
+	}
+
+	signature := t.GetSignature()
+	signingString := t.GetMsg()
+
+	// check signature
+	verifyStatus, err := signer.Verify([]byte(signingString), signature, key)
+	if !verifyStatus || err != nil {
+		return nil, newValidationError(ErrJWTVerifyFail, ErrJWTTokenSignatureInvalid).
+			withCauses(err)
+	}
+
+	return t, nil
+}
+
+// ParseWithKeySet parses the signature and returns the parsed token.
+// The keys are selected by the `kid` header, when the token has no `kid`,
+// every key compatible with the `alg` header is tried in turn.
+// The returned errors are *ValidationError.
+func ParseWithKeySet(tokenString string, keySet IKeySet, opt ...ParserOption) (*Token, error) {
+	parserOpt := getParserOption(opt)
+
+	t, err := parseToken(tokenString, parserOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := checkTokenHeader(t, parserOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	if GetSigningMethod(alg) == nil {
+		return nil, newValidationError(ErrJWTMethodExists, ErrJWTTokenUnverifiable)
+	}
+
+	header, err := t.GetHeader()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	kid, err := header.GetKeyID()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	keys, err := keySet.LookupKeys(kid)
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenUnverifiable)
+	}
+
+	signature := t.GetSignature()
+	signingString := t.GetMsg()
+
+	var compatible bool
+	var verifyErrs []error
+	for _, key := range keys {
+		if !key.Compatible(alg) {
+			continue
+		}
+
+		compatible = true
+
+		// check signature
+		verifyStatus, err := key.Verify(alg, []byte(signingString), signature)
+		if verifyStatus && err == nil {
+			if err := validateTokenClaims(t, parserOpt); err != nil {
+				return nil, err
+			}
+
+			return t, nil
+		}
+
+		verifyErrs = append(verifyErrs, err)
+	}
+
+	if !compatible {
+		return nil, newValidationError(ErrKeySetKeyNotFound, ErrJWTTokenUnverifiable)
+	}
+
+	return nil, newValidationError(ErrJWTVerifyFail, ErrJWTTokenSignatureInvalid).
+		withCauses(verifyErrs...)
+}
+
+// return the first parser option, or the default parser option
+func getParserOption(opt []ParserOption) ParserOption {
+	if len(opt) > 0 {
+		return opt[0]
+	}
+
+	return ParserOption{
+		Encoder: JWTEncoder,
+	}
+}
+
+// parse the token string parts
+func parseToken(tokenString string, parserOpt ParserOption) (*Token, error) {
 	// if not set encoder, return error
 	if parserOpt.Encoder == nil {
-		return nil, ErrJWTEncoderInvalid
+		return nil, newValidationError(ErrJWTEncoderInvalid, ErrJWTTokenUnverifiable)
 	}
 
 	t := NewToken(parserOpt.Encoder)
-	t.Parse(tokenString)
+	headerErr, claimsErr, signatureErr := t.parseParts(tokenString)
 
 	if t.GetPartCount() < 2 {
-		return nil, ErrJWTTokenInvalid
+		return nil, newValidationError(ErrJWTTokenInvalid, ErrJWTTokenMalformed)
 	}
 
-	key, err := keyFunc(t)
-	if err != nil {
-		return nil, err
+	if err := errors.Join(headerErr, claimsErr); err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	// a signature can not be decoded is invalid
+	if signatureErr != nil {
+		return nil, newValidationError(ErrJWTVerifyFail, ErrJWTTokenSignatureInvalid, ErrJWTTokenMalformed).
+			withCauses(signatureErr)
 	}
 
+	return t, nil
+}
+
+// check token header type and algo, returns the algo
+func checkTokenHeader(t *Token, parserOpt ParserOption) (string, error) {
 	header, err := t.GetHeader()
 	if err != nil {
-		return nil, err
+		return "", newValidationError(err, ErrJWTTokenMalformed)
 	}
 
 	typ, err := header.GetType()
 	if err != nil {
-		return nil, err
+		return "", newValidationError(err, ErrJWTTokenMalformed)
 	}
 
 	// if token type not empty and not equal JWT, return error
 	if len(typ) > 0 && typ != "JWT" {
-		return nil, ErrJWTTypeInvalid
+		return "", newValidationError(ErrJWTTypeInvalid, ErrJWTTokenMalformed)
 	}
 
 	alg, err := header.GetAlgorithm()
 	if err != nil {
-		return nil, err
+		return "", newValidationError(err, ErrJWTTokenMalformed)
 	}
 
 	// Verify signing method is in the required set
@@ -78,28 +295,10 @@ func Parse[V any](tokenString string, keyFunc func(t *Token) (key V, err error),
 		}
 
 		if !signingMethodValid {
-			return nil, NewError(fmt.Sprintf("signing method %v is invalid", alg), ErrJWTTokenSignatureInvalid)
+			err := NewError(fmt.Sprintf("signing method %v is invalid", alg), ErrJWTTokenSignatureInvalid)
+			return "", newValidationError(err, ErrJWTTokenUnverifiable)
 		}
 	}
 
-	signingMethod := GetSigningMethod(alg)
-	if signingMethod == nil {
-		return nil, ErrJWTMethodExists
-	}
-
-	signer, ok := signingMethod.(IVerifying[V])
-	if !ok {
-		return nil, ErrJWTMethodInvalid
-	}
-
-	signature := t.GetSignature()
-	signingString := t.GetMsg()
-
-	// check signature
-	verifyStatus, _ := signer.Verify([]byte(signingString), signature, key)
-	if !verifyStatus {
-		return nil, ErrJWTVerifyFail
-	}
-
-	return t, nil
+	return alg, nil
 }
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrJWTTokenExpired              = errors.New("go-jwt: token is expired")
+	ErrJWTTokenNotValidYet          = errors.New("go-jwt: token is not valid yet")
+	ErrJWTTokenUsedBeforeIssued     = errors.New("go-jwt: token used before issued")
+	ErrJWTTokenInvalidIssuer        = errors.New("go-jwt: token has invalid issuer")
+	ErrJWTTokenInvalidAudience      = errors.New("go-jwt: token has invalid audience")
+	ErrJWTTokenInvalidSubject       = errors.New("go-jwt: token has invalid subject")
+	ErrJWTTokenRequiredClaimMissing = errors.New("go-jwt: token is missing required claim")
+)
+
+// return the parser option current time
+func (opt ParserOption) now() time.Time {
+	return clockNow(opt.Clock)
+}
+
+// return true when the parser option checks the token claims
+func (opt ParserOption) checkClaims() bool {
+	return !opt.SkipClaimsValidation || opt.Revocation != nil || opt.ReplayCache != nil
+}
+
+// the token which has the claims, a *Token or an *EncryptedToken
+type claimsToken interface {
+	GetClaims() (MapClaims, error)
+}
+
+// validate the token claims, the revocation and the replay with the parser option
+func validateTokenClaims(t claimsToken, parserOpt ParserOption) error {
+	if !parserOpt.checkClaims() {
+		return nil
+	}
+
+	claims, err := t.GetClaims()
+	if err != nil {
+		return newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	if !parserOpt.SkipClaimsValidation {
+		if err := validateClaims(claims, claims, parserOpt); err != nil {
+			return newClaimsValidationError(err)
+		}
+	}
+
+	if err := checkRevocation(claims, claims, parserOpt); err != nil {
+		return err
+	}
+
+	return checkReplay(claims, claims, parserOpt)
+}
+
+// validate the claims time and identity, see RFC 7519 section 4.1.
+// The raw map claims is used to check the claims existence.
+func validateClaims(claims Claims, raw MapClaims, parserOpt ParserOption) error {
+	for _, name := range parserOpt.RequiredClaims {
+		if _, ok := raw[name]; !ok {
+			return NewError(name, ErrJWTTokenRequiredClaimMissing)
+		}
+	}
+
+	now := parserOpt.now()
+	leeway := parserOpt.Leeway
+
+	if _, ok := raw["exp"]; ok {
+		exp, err := claims.GetExpirationTime()
+		if err != nil {
+			return err
+		}
+
+		// the token must not be accepted on or after the `exp` time
+		if !now.Before(claimDate(exp).Add(leeway)) {
+			return ErrJWTTokenExpired
+		}
+	}
+
+	if _, ok := raw["nbf"]; ok {
+		nbf, err := claims.GetNotBefore()
+		if err != nil {
+			return err
+		}
+
+		if now.Add(leeway).Before(claimDate(nbf)) {
+			return ErrJWTTokenNotValidYet
+		}
+	}
+
+	if _, ok := raw["iat"]; ok {
+		iat, err := claims.GetIssuedAt()
+		if err != nil {
+			return err
+		}
+
+		if now.Add(leeway).Before(claimDate(iat)) {
+			return ErrJWTTokenUsedBeforeIssued
+		}
+	}
+
+	if parserOpt.Issuer != "" {
+		iss, err := claims.GetIssuer()
+		if err != nil {
+			return err
+		}
+
+		if iss != parserOpt.Issuer {
+			return ErrJWTTokenInvalidIssuer
+		}
+	}
+
+	if len(parserOpt.Audience) > 0 {
+		aud, err := claims.GetAudience()
+		if err != nil {
+			return err
+		}
+
+		if !slices.ContainsFunc(aud.Value, func(a string) bool {
+			return slices.Contains(parserOpt.Audience, a)
+		}) {
+			return ErrJWTTokenInvalidAudience
+		}
+	}
+
+	if parserOpt.Subject != "" {
+		sub, err := claims.GetSubject()
+		if err != nil {
+			return err
+		}
+
+		if sub != parserOpt.Subject {
+			return ErrJWTTokenInvalidSubject
+		}
+	}
+
+	return nil
+}
+
+// return the time of a present time claim, the zero claim value
+// is returned as nil by MapClaims, and it is the epoch time
+func claimDate(date *NumericDate) time.Time {
+	if date == nil {
+		return time.Unix(0, 0)
+	}
+
+	return date.Time
+}
@@ -0,0 +1,332 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deatil/go-jwt/encoder"
+)
+
+func Test_Parse_ValidateClaims(t *testing.T) {
+	key := []byte("test-key")
+	now := time.Unix(1700000000, 0)
+
+	keyFunc := func(t *Token) ([]byte, error) {
+		return key, nil
+	}
+	clock := NewFrozenClock(now)
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		opt    ParserOption
+		err    error
+	}{
+		{
+			name: "valid",
+			claims: map[string]any{
+				"iss": "issuer",
+				"aud": []string{"a", "b"},
+				"sub": "subject",
+				"exp": now.Unix() + 60,
+				"nbf": now.Unix() - 60,
+				"iat": now.Unix() - 60,
+			},
+			opt: ParserOption{
+				Issuer:         "issuer",
+				Audience:       []string{"b", "c"},
+				Subject:        "subject",
+				RequiredClaims: []string{"exp", "sub"},
+			},
+		},
+		{
+			name:   "expired",
+			claims: map[string]any{"exp": now.Unix()},
+			err:    ErrJWTTokenExpired,
+		},
+		{
+			name:   "expired with leeway",
+			claims: map[string]any{"exp": now.Unix() - 10},
+			opt:    ParserOption{Leeway: 20 * time.Second},
+		},
+		{
+			name:   "not valid yet",
+			claims: map[string]any{"nbf": now.Unix() + 10},
+			err:    ErrJWTTokenNotValidYet,
+		},
+		{
+			name:   "not valid yet with leeway",
+			claims: map[string]any{"nbf": now.Unix() + 10},
+			opt:    ParserOption{Leeway: 20 * time.Second},
+		},
+		{
+			name:   "used before issued",
+			claims: map[string]any{"iat": now.Unix() + 10},
+			err:    ErrJWTTokenUsedBeforeIssued,
+		},
+		{
+			name:   "expired at the epoch",
+			claims: map[string]any{"exp": 0},
+			err:    ErrJWTTokenExpired,
+		},
+		{
+			name:   "epoch nbf and iat",
+			claims: map[string]any{"nbf": 0, "iat": 0},
+		},
+		{
+			name:   "invalid exp",
+			claims: map[string]any{"exp": "tomorrow"},
+			err:    ErrJWTInvalidType,
+		},
+		{
+			name:   "invalid issuer",
+			claims: map[string]any{"iss": "other"},
+			opt:    ParserOption{Issuer: "issuer"},
+			err:    ErrJWTTokenInvalidIssuer,
+		},
+		{
+			name:   "invalid audience",
+			claims: map[string]any{"aud": "other"},
+			opt:    ParserOption{Audience: []string{"a"}},
+			err:    ErrJWTTokenInvalidAudience,
+		},
+		{
+			name:   "missing audience",
+			claims: map[string]any{},
+			opt:    ParserOption{Audience: []string{"a"}},
+			err:    ErrJWTTokenInvalidAudience,
+		},
+		{
+			name:   "invalid subject",
+			claims: map[string]any{"sub": "other"},
+			opt:    ParserOption{Subject: "subject"},
+			err:    ErrJWTTokenInvalidSubject,
+		},
+		{
+			name:   "required claim missing",
+			claims: map[string]any{"sub": "subject"},
+			opt:    ParserOption{RequiredClaims: []string{"exp"}},
+			err:    ErrJWTTokenRequiredClaimMissing,
+		},
+		{
+			name:   "skip validation",
+			claims: map[string]any{"exp": now.Unix() - 10},
+			opt:    ParserOption{SkipClaimsValidation: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenString, err := SigningMethodHS256.Sign(tt.claims, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opt := tt.opt
+			opt.Encoder = JWTEncoder
+			opt.Clock = clock
+
+			_, err = Parse(tokenString, keyFunc, opt)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Parse got %v, want %v", err, tt.err)
+			}
+
+			_, err = SigningMethodHS256.Parse(tokenString, key, opt)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("JWT.Parse got %v, want %v", err, tt.err)
+			}
+
+			_, err = ParseWithKeySet(tokenString, NewKeySet(NewKeySetKey("", key)), opt)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseWithKeySet got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_Parse_ValidateClaims_Default(t *testing.T) {
+	key := []byte("test-key")
+
+	tokenString, err := SigningMethodHS256.Sign(map[string]any{
+		"exp": time.Now().Add(-time.Minute).Unix(),
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Parse(tokenString, func(t *Token) ([]byte, error) {
+		return key, nil
+	})
+	if !errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenExpired)
+	}
+
+	_, err = SigningMethodHS256.Parse(tokenString, key)
+	if !errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("JWT.Parse got %v, want %v", err, ErrJWTTokenExpired)
+	}
+}
+
+func Test_Parse_ValidateClaims_NotFinite(t *testing.T) {
+	key := []byte("test-key")
+
+	tokenString, err := SigningMethodHS256.Sign(map[string]any{
+		"exp": json.RawMessage("1e400"),
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Parse(tokenString, func(t *Token) ([]byte, error) {
+		return key, nil
+	}, ParserOption{
+		Encoder: encoder.NewJoseEncoder(encoder.WithJSONNumber()),
+	})
+	if !errors.Is(err, ErrJWTTokenInvalidClaims) || errors.Is(err, ErrJWTTokenExpired) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenInvalidClaims)
+	}
+}
+
+type testUserClaims struct {
+	RegisteredClaims
+
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+func Test_ParseWithClaims(t *testing.T) {
+	key := []byte("test-key")
+	now := time.Now()
+
+	keyFunc := func(t *Token) ([]byte, error) {
+		return key, nil
+	}
+
+	tokenString, err := SigningMethodHS256.Sign(testUserClaims{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:    "issuer",
+			Subject:   "subject",
+			Audience:  NewClaimSingleString("example.com"),
+			ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  NewNumericDate(now),
+		},
+		Name:  "foo",
+		Admin: true,
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opt := ParserOption{
+		Encoder:  JWTEncoder,
+		Issuer:   "issuer",
+		Audience: []string{"example.com"},
+	}
+
+	{
+		claims, token, err := ParseWithClaims[testUserClaims](tokenString, keyFunc, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token.GetRaw() != tokenString {
+			t.Errorf("GetRaw got %s, want %s", token.GetRaw(), tokenString)
+		}
+		if claims.Name != "foo" {
+			t.Errorf("Name got %s, want %s", claims.Name, "foo")
+		}
+		if !claims.Admin {
+			t.Error("Admin got false, want true")
+		}
+		if claims.Subject != "subject" {
+			t.Errorf("Subject got %s, want %s", claims.Subject, "subject")
+		}
+	}
+
+	{
+		claims, _, err := ParseWithClaims[*testUserClaims](tokenString, keyFunc, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if claims.Name != "foo" {
+			t.Errorf("Name got %s, want %s", claims.Name, "foo")
+		}
+	}
+
+	{
+		claims, _, err := ParseWithClaims[MapClaims](tokenString, keyFunc, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name, _ := claims.GetString("name")
+		if name != "foo" {
+			t.Errorf("name got %s, want %s", name, "foo")
+		}
+	}
+
+	{
+		opt2 := opt
+		opt2.Clock = NewFrozenClock(now.Add(2 * time.Hour))
+
+		_, token, err := ParseWithClaims[*testUserClaims](tokenString, keyFunc, opt2)
+		if !errors.Is(err, ErrJWTTokenExpired) {
+			t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenExpired)
+		}
+		if token != nil {
+			t.Error("ParseWithClaims token should be nil")
+		}
+	}
+
+	{
+		opt2 := opt
+		opt2.Issuer = "other"
+
+		_, _, err := ParseWithClaims[testUserClaims](tokenString, keyFunc, opt2)
+		if !errors.Is(err, ErrJWTTokenInvalidIssuer) {
+			t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenInvalidIssuer)
+		}
+	}
+
+	{
+		_, _, err := ParseWithClaims[testUserClaims](tokenString, func(t *Token) ([]byte, error) {
+			return []byte("other-key"), nil
+		})
+		if !errors.Is(err, ErrJWTTokenSignatureInvalid) {
+			t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenSignatureInvalid)
+		}
+	}
+}
+
+func Test_ParseWithClaims_Malformed(t *testing.T) {
+	key := []byte("test-key")
+
+	keyFunc := func(t *Token) ([]byte, error) {
+		return key, nil
+	}
+
+	tokenString, err := SigningMethodHS256.Sign(map[string]any{
+		"name": 123,
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = ParseWithClaims[*testUserClaims](tokenString, keyFunc)
+	if !errors.Is(err, ErrJWTTokenMalformed) {
+		t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenMalformed)
+	}
+
+	nullToken, err := SigningMethodHS256.Sign(nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = ParseWithClaims[*testUserClaims](nullToken, keyFunc)
+	if !errors.Is(err, ErrJWTTokenMalformed) {
+		t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenMalformed)
+	}
+}
@@ -0,0 +1,569 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"hash"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrPKCS8PasswordRequired      = errors.New("go-jwt: PKCS8 private key is encrypted, a password is required")
+	ErrPKCS8DecryptFail           = errors.New("go-jwt: PKCS8 private key decrypt fail, the password may be invalid")
+	ErrPKCS8EncryptionUnsupported = errors.New("go-jwt: PKCS8 private key encryption unsupported")
+	ErrPKCS8EncryptOptionsInvalid = errors.New("go-jwt: PKCS8 encrypt options invalid")
+)
+
+const (
+	// PEM block type of the encrypted PKCS8 private key, see RFC 7468 section 11
+	EncryptedPrivateKeyPEMType = "ENCRYPTED PRIVATE KEY"
+
+	// default PBKDF2 iteration count of the encrypted keys
+	DefaultPBKDF2Iterations = 600000
+
+	// default scrypt cost parameters of the encrypted keys, same as OpenSSL
+	DefaultScryptN = 1 << 14
+	DefaultScryptR = 8
+	DefaultScryptP = 1
+
+	// default salt size of the key derivation
+	DefaultPKCS8SaltSize = 16
+
+	// nonce size of the AES-GCM encryption
+	gcmStandardNonceSize = 12
+
+	// max PBKDF2 iteration count, a key file can not use more CPU
+	maxPBKDF2Iterations = 10000000
+
+	// max scrypt memory and work as 128 * N * r * p bytes,
+	// a key file can not use more memory and CPU
+	maxScryptCost = 256 << 20
+)
+
+// PKCS8KDF is the password based key derivation function
+type PKCS8KDF int
+
+const (
+	// PBKDF2 with HMAC-SHA256, see RFC 8018 section 5.2
+	PKCS8PBKDF2 PKCS8KDF = iota
+
+	// scrypt, see RFC 7914 section 7
+	PKCS8Scrypt
+)
+
+// PKCS8Cipher is the content encryption of the private key
+type PKCS8Cipher int
+
+const (
+	PKCS8AES256CBC PKCS8Cipher = iota
+	PKCS8AES192CBC
+	PKCS8AES128CBC
+	PKCS8AES256GCM
+	PKCS8AES192GCM
+	PKCS8AES128GCM
+)
+
+// PKCS8EncryptOptions sets how a private key is encrypted, the zero
+// value is PBKDF2 with HMAC-SHA256 and AES-256-CBC.
+type PKCS8EncryptOptions struct {
+	// the key derivation function
+	KDF PKCS8KDF
+
+	// the content encryption
+	Cipher PKCS8Cipher
+
+	// PBKDF2 iteration count, DefaultPBKDF2Iterations is used when 0
+	Iterations int
+
+	// scrypt cost parameters, the defaults are used when 0
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	// salt size, DefaultPKCS8SaltSize is used when 0
+	SaltSize int
+}
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidScrypt = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidAES128GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 6}
+	oidAES192GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 26}
+	oidAES256GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}
+)
+
+// EncryptedPrivateKeyInfo, see RFC 5958 section 3
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+// PBES2-params, see RFC 8018 appendix A.4
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// PBKDF2-params, see RFC 8018 appendix A.2
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// scrypt-params, see RFC 7914 section 7.1
+type scryptParams struct {
+	Salt                     []byte
+	CostParameter            int
+	BlockSize                int
+	ParallelizationParameter int
+	KeyLength                int `asn1:"optional"`
+}
+
+// GCMParameters, see RFC 5084 section 3.2
+type gcmParams struct {
+	Nonce  []byte
+	ICVLen int `asn1:"optional,default:12"`
+}
+
+// the AES cipher of the encryption scheme
+type pkcs8Cipher struct {
+	oid     asn1.ObjectIdentifier
+	keySize int
+	gcm     bool
+}
+
+var pkcs8Ciphers = map[PKCS8Cipher]pkcs8Cipher{
+	PKCS8AES128CBC: {oidAES128CBC, 16, false},
+	PKCS8AES192CBC: {oidAES192CBC, 24, false},
+	PKCS8AES256CBC: {oidAES256CBC, 32, false},
+	PKCS8AES128GCM: {oidAES128GCM, 16, true},
+	PKCS8AES192GCM: {oidAES192GCM, 24, true},
+	PKCS8AES256GCM: {oidAES256GCM, 32, true},
+}
+
+// ParsePKCS8PrivateKeyFromEncryptedPEM parses a PEM encoded encrypted
+// PKCS8 private key, the key is *rsa.PrivateKey, *ecdsa.PrivateKey,
+// ed25519.PrivateKey or *ecdh.PrivateKey. A not encrypted
+// PRIVATE KEY block is parsed as it is.
+func ParsePKCS8PrivateKeyFromEncryptedPEM(key []byte, password []byte) (any, error) {
+	der, err := parsePrivateKeyPEM(key, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParsePKCS8PrivateKey(der)
+}
+
+// ParsePKCS8PrivateKeyFromEncryptedDer parses a der encoded encrypted PKCS8 private key
+func ParsePKCS8PrivateKeyFromEncryptedDer(der []byte, password []byte) (any, error) {
+	der, err := DecryptPKCS8PrivateKey(der, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParsePKCS8PrivateKey(der)
+}
+
+// MarshalPKCS8PrivateKeyToEncryptedPEM returns the PEM encoded encrypted PKCS8 private key
+func MarshalPKCS8PrivateKeyToEncryptedPEM(key any, password []byte, opts ...PKCS8EncryptOptions) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKeyToEncryptedDer(key, password, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodePEM(EncryptedPrivateKeyPEMType, der), nil
+}
+
+func MarshalPKCS8PrivateKeyToEncryptedDer(key any, password []byte, opts ...PKCS8EncryptOptions) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncryptPKCS8PrivateKey(der, password, opts...)
+}
+
+// EncryptPKCS8PrivateKey encrypts the der encoded PKCS8 private key with
+// PBES2, and returns the der encoded EncryptedPrivateKeyInfo.
+func EncryptPKCS8PrivateKey(der []byte, password []byte, opts ...PKCS8EncryptOptions) ([]byte, error) {
+	var opt PKCS8EncryptOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	c, ok := pkcs8Ciphers[opt.Cipher]
+	if !ok {
+		return nil, NewError("cipher", ErrPKCS8EncryptOptionsInvalid)
+	}
+
+	saltSize := opt.SaltSize
+	if saltSize == 0 {
+		saltSize = DefaultPKCS8SaltSize
+	}
+	if saltSize < 8 {
+		return nil, NewError("salt size", ErrPKCS8EncryptOptionsInvalid)
+	}
+
+	salt, err := randomBytes(saltSize)
+	if err != nil {
+		return nil, err
+	}
+
+	kdf, key, err := deriveEncryptKey(opt, password, salt, c.keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var params, encrypted []byte
+	if c.gcm {
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		nonce, err := randomBytes(aead.NonceSize())
+		if err != nil {
+			return nil, err
+		}
+
+		params, err = asn1.Marshal(gcmParams{
+			Nonce:  nonce,
+			ICVLen: aead.Overhead(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encrypted = aead.Seal(nil, nonce, der, nil)
+	} else {
+		iv, err := randomBytes(block.BlockSize())
+		if err != nil {
+			return nil, err
+		}
+
+		params, err = asn1.Marshal(iv)
+		if err != nil {
+			return nil, err
+		}
+
+		encrypted = pkcs7Padding(der, block.BlockSize())
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+	}
+
+	pbes2, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: kdf,
+		EncryptionScheme: pkix.AlgorithmIdentifier{
+			Algorithm:  c.oid,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		EncryptionAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: pbes2},
+		},
+		EncryptedData: encrypted,
+	})
+}
+
+// DecryptPKCS8PrivateKey decrypts the der encoded EncryptedPrivateKeyInfo,
+// and returns the der encoded PKCS8 private key. Only PBES2 with PBKDF2
+// or scrypt, and AES-CBC or AES-GCM is supported.
+func DecryptPKCS8PrivateKey(der []byte, password []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, asn1.SyntaxError{Msg: "trailing data"}
+	}
+
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, NewError(info.EncryptionAlgorithm.Algorithm.String(), ErrPKCS8EncryptionUnsupported)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+
+	c, err := parseEncryptionScheme(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveDecryptKey(params.KeyDerivationFunc, password, c.keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := info.EncryptedData
+	scheme := params.EncryptionScheme.Parameters.FullBytes
+
+	if c.gcm {
+		var gcm gcmParams
+		if _, err := asn1.Unmarshal(scheme, &gcm); err != nil {
+			return nil, err
+		}
+
+		if len(gcm.Nonce) != gcmStandardNonceSize {
+			return nil, NewError("GCM nonce size", ErrPKCS8EncryptionUnsupported)
+		}
+
+		aead, err := cipher.NewGCMWithTagSize(block, gcm.ICVLen)
+		if err != nil {
+			return nil, NewError("GCM tag size", ErrPKCS8EncryptionUnsupported)
+		}
+
+		plaintext, err := aead.Open(nil, gcm.Nonce, encrypted, nil)
+		if err != nil {
+			return nil, ErrPKCS8DecryptFail
+		}
+
+		return plaintext, nil
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(scheme, &iv); err != nil {
+		return nil, err
+	}
+
+	if len(iv) != block.BlockSize() {
+		return nil, NewError("CBC IV size", ErrPKCS8EncryptionUnsupported)
+	}
+	if len(encrypted) == 0 || len(encrypted)%block.BlockSize() != 0 {
+		return nil, ErrPKCS8DecryptFail
+	}
+
+	plaintext := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, encrypted)
+
+	plaintext, err = pkcs7UnPadding(plaintext, block.BlockSize())
+	if err != nil {
+		return nil, ErrPKCS8DecryptFail
+	}
+
+	// the padding of a wrong password is valid sometimes,
+	// the plaintext must be one ASN.1 value
+	var raw asn1.RawValue
+	if rest, err := asn1.Unmarshal(plaintext, &raw); err != nil || len(rest) > 0 {
+		return nil, ErrPKCS8DecryptFail
+	}
+
+	return plaintext, nil
+}
+
+// parse the private key PEM, the encrypted PKCS8 private key
+// is decrypted with the password
+func parsePrivateKeyPEM(data []byte, password []byte) ([]byte, error) {
+	var block *pem.Block
+	if block, _ = pem.Decode(data); block == nil {
+		return nil, ErrPEMInvalid
+	}
+
+	if block.Type != EncryptedPrivateKeyPEMType {
+		return block.Bytes, nil
+	}
+
+	if password == nil {
+		return nil, ErrPKCS8PasswordRequired
+	}
+
+	return DecryptPKCS8PrivateKey(block.Bytes, password)
+}
+
+// return the AES cipher of the encryption scheme oid
+func parseEncryptionScheme(oid asn1.ObjectIdentifier) (pkcs8Cipher, error) {
+	for _, c := range pkcs8Ciphers {
+		if c.oid.Equal(oid) {
+			return c, nil
+		}
+	}
+
+	return pkcs8Cipher{}, NewError(oid.String(), ErrPKCS8EncryptionUnsupported)
+}
+
+// derive the encryption key with the options, and return the kdf identifier
+func deriveEncryptKey(opt PKCS8EncryptOptions, password, salt []byte, keySize int) (pkix.AlgorithmIdentifier, []byte, error) {
+	var kdf pkix.AlgorithmIdentifier
+	var key, params []byte
+	var err error
+
+	switch opt.KDF {
+	case PKCS8PBKDF2:
+		iterations := opt.Iterations
+		if iterations == 0 {
+			iterations = DefaultPBKDF2Iterations
+		}
+		if !validPBKDF2Iterations(iterations) {
+			return kdf, nil, NewError("iterations", ErrPKCS8EncryptOptionsInvalid)
+		}
+
+		key, err = pbkdf2.Key(sha256.New, string(password), salt, iterations, keySize)
+		if err != nil {
+			return kdf, nil, err
+		}
+
+		params, err = asn1.Marshal(pbkdf2Params{
+			Salt:           salt,
+			IterationCount: iterations,
+			PRF: pkix.AlgorithmIdentifier{
+				Algorithm:  oidHMACWithSHA256,
+				Parameters: asn1.NullRawValue,
+			},
+		})
+		kdf.Algorithm = oidPBKDF2
+	case PKCS8Scrypt:
+		n, r, p := opt.ScryptN, opt.ScryptR, opt.ScryptP
+		if n == 0 {
+			n = DefaultScryptN
+		}
+		if r == 0 {
+			r = DefaultScryptR
+		}
+		if p == 0 {
+			p = DefaultScryptP
+		}
+		if !validScryptParams(n, r, p) {
+			return kdf, nil, NewError("scrypt parameters", ErrPKCS8EncryptOptionsInvalid)
+		}
+
+		key, err = scrypt.Key(password, salt, n, r, p, keySize)
+		if err != nil {
+			return kdf, nil, NewError(err.Error(), ErrPKCS8EncryptOptionsInvalid)
+		}
+
+		params, err = asn1.Marshal(scryptParams{
+			Salt:                     salt,
+			CostParameter:            n,
+			BlockSize:                r,
+			ParallelizationParameter: p,
+		})
+		kdf.Algorithm = oidScrypt
+	default:
+		return kdf, nil, NewError("kdf", ErrPKCS8EncryptOptionsInvalid)
+	}
+
+	if err != nil {
+		return kdf, nil, err
+	}
+
+	kdf.Parameters = asn1.RawValue{FullBytes: params}
+
+	return kdf, key, nil
+}
+
+// derive the decryption key with the kdf identifier
+func deriveDecryptKey(kdf pkix.AlgorithmIdentifier, password []byte, keySize int) ([]byte, error) {
+	switch {
+	case kdf.Algorithm.Equal(oidPBKDF2):
+		var params pbkdf2Params
+		if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+
+		if params.KeyLength != 0 && params.KeyLength != keySize {
+			return nil, NewError("PBKDF2 key length", ErrPKCS8EncryptionUnsupported)
+		}
+		if !validPBKDF2Iterations(params.IterationCount) {
+			return nil, NewError("PBKDF2 iteration count", ErrPKCS8EncryptionUnsupported)
+		}
+
+		h, err := pbkdf2PRF(params.PRF.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		return pbkdf2.Key(h, string(password), params.Salt, params.IterationCount, keySize)
+	case kdf.Algorithm.Equal(oidScrypt):
+		var params scryptParams
+		if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+
+		if params.KeyLength != 0 && params.KeyLength != keySize {
+			return nil, NewError("scrypt key length", ErrPKCS8EncryptionUnsupported)
+		}
+		if !validScryptParams(params.CostParameter, params.BlockSize, params.ParallelizationParameter) {
+			return nil, NewError("scrypt parameters", ErrPKCS8EncryptionUnsupported)
+		}
+
+		key, err := scrypt.Key(password, params.Salt, params.CostParameter, params.BlockSize, params.ParallelizationParameter, keySize)
+		if err != nil {
+			return nil, NewError(err.Error(), ErrPKCS8EncryptionUnsupported)
+		}
+
+		return key, nil
+	}
+
+	return nil, NewError(kdf.Algorithm.String(), ErrPKCS8EncryptionUnsupported)
+}
+
+// return the hash of the PBKDF2 prf, the default prf is HMAC-SHA1
+func pbkdf2PRF(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHMACWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHMACWithSHA224):
+		return sha256.New224, nil
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHMACWithSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidHMACWithSHA512):
+		return sha512.New, nil
+	}
+
+	return nil, NewError(oid.String(), ErrPKCS8EncryptionUnsupported)
+}
+
+// check the PBKDF2 iteration count is in the limit
+func validPBKDF2Iterations(iterations int) bool {
+	return iterations >= 1 && iterations <= maxPBKDF2Iterations
+}
+
+// check the scrypt parameters are in the cost limit
+func validScryptParams(n, r, p int) bool {
+	if n < 2 || r < 1 || p < 1 {
+		return false
+	}
+
+	limit := maxScryptCost / 128
+	return r <= limit && n <= limit/r && p <= limit/(r*n)
+}
@@ -0,0 +1,136 @@
+package jwt
+
+import (
+	"errors"
+	"maps"
+	"time"
+)
+
+var (
+	ErrJWTRefreshLifetimeInvalid = errors.New("go-jwt: refresh token lifetime invalid")
+)
+
+// RefreshOption sets the new token claims when refreshes a token
+type RefreshOption struct {
+	// set the `exp` claim with the duration from the clock time,
+	// 0 keeps the token lifetime from the `iat` and `exp` claims
+	ExpiresIn time.Duration
+
+	// keep the `jti` claim, a new `jti` is set when false
+	KeepID bool
+
+	// the `kid` header of the signing key, the token `kid` names
+	// the old signing key and it is not kept
+	KeyID string
+
+	// the claims replace the token claims
+	Claims MapClaims
+}
+
+// NewBuilderFromToken returns a builder with the token headers and claims.
+// The `alg` and `kid` headers are not copied, they name the old signing
+// method and key. The `alg` header is set from the signer.
+func NewBuilderFromToken[S any](t *Token, signer ISigning[S], encoder IEncoder) (*Builder[S], error) {
+	headers, err := t.GetHeader()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	claims, err := t.GetClaims()
+	if err != nil {
+		return nil, newValidationError(err, ErrJWTTokenMalformed)
+	}
+
+	b := NewBuilder(signer, encoder)
+	b.headers = maps.Clone(headers)
+	b.claims = maps.Clone(claims)
+
+	delete(b.headers, RegisteredStdHeaders.Algorithm)
+	delete(b.headers, RegisteredStdHeaders.KeyID)
+
+	return b, nil
+}
+
+// return a new *Builder with the token headers and claims.
+func (jwt *JWT[S, V]) Rebuild(t *Token) (*Builder[S], error) {
+	b, err := NewBuilderFromToken[S](t, jwt.signer, jwt.encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.WithKeyIDThumbprint(jwt.kidHash).
+		WithClock(jwt.clock).
+		WithPolicy(jwt.builderPolicy), nil
+}
+
+// Refresh returns a new signed token with the claims of a verified token.
+// The `iat` claim is set with the clock time, the `exp` and `jti` claims
+// are set with the option, the headers like `cty` are kept. The `kid`
+// header is set with the option or the key thumbprint. The token can be
+// refreshed with a different signer and key. A token with `exp` and
+// without `iat` needs the option ExpiresIn.
+func (jwt *JWT[S, V]) Refresh(t *Token, signKey S, opt ...RefreshOption) (*Token, error) {
+	var refreshOpt RefreshOption
+	if len(opt) > 0 {
+		refreshOpt = opt[0]
+	}
+
+	b, err := jwt.Rebuild(t)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresIn := refreshOpt.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn, err = tokenLifetime(MapClaims(b.claims))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if refreshOpt.KeyID != "" {
+		b.WithHeader(RegisteredStdHeaders.KeyID, refreshOpt.KeyID)
+	}
+
+	b.IssuedNow()
+	if expiresIn != 0 {
+		b.ExpiresIn(expiresIn)
+	}
+	if !refreshOpt.KeepID {
+		b.WithRandomID()
+	}
+	if len(refreshOpt.Claims) > 0 {
+		b.WithClaims(refreshOpt.Claims)
+	}
+
+	return b.GetToken(signKey)
+}
+
+// return the token lifetime from the `iat` and `exp` claims,
+// 0 is returned when the token has no `exp` claim
+func tokenLifetime(claims MapClaims) (time.Duration, error) {
+	if _, ok := claims[RegisteredStdClaims.ExpirationTime]; !ok {
+		return 0, nil
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return 0, NewError("exp", ErrJWTRefreshLifetimeInvalid, err)
+	}
+
+	if _, ok := claims[RegisteredStdClaims.IssuedAt]; !ok {
+		return 0, NewError("the token has no iat, set the ExpiresIn", ErrJWTRefreshLifetimeInvalid)
+	}
+
+	iat, err := claims.GetIssuedAt()
+	if err != nil {
+		return 0, NewError("iat", ErrJWTRefreshLifetimeInvalid, err)
+	}
+
+	lifetime := claimDate(exp).Sub(claimDate(iat))
+	if lifetime <= 0 {
+		return 0, NewError("exp is not after iat", ErrJWTRefreshLifetimeInvalid)
+	}
+
+	return lifetime, nil
+}
@@ -0,0 +1,264 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Refresh(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	s := SigningMethodHS256.New().WithClock(clock)
+
+	token, err := s.Build().
+		WithHeader("kid", "key1").
+		WithHeader("cty", "JWT").
+		RelatedTo("subject").
+		WithClaim("role", "admin").
+		IdentifiedBy("id-1").
+		IssuedNow().
+		ExpiresIn(time.Hour).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenString, err := token.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := s.Parse(tokenString, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(30 * time.Minute)
+
+	refreshed, err := s.Refresh(parsed, key, RefreshOption{
+		KeyID:  "key1",
+		Claims: MapClaims{"role": "user"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers, err := refreshed.GetHeaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if headers["kid"] != "key1" {
+		t.Errorf("kid got %s, want %s", headers["kid"], "key1")
+	}
+	if headers["cty"] != "JWT" {
+		t.Errorf("cty got %s, want %s", headers["cty"], "JWT")
+	}
+
+	claims, err := refreshed.GetClaims()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iat, _ := claims.GetIssuedAt()
+	if iat.Unix() != 1700001800 {
+		t.Errorf("iat got %d, want %d", iat.Unix(), 1700001800)
+	}
+
+	// the token lifetime is kept
+	exp, _ := claims.GetExpirationTime()
+	if exp.Unix() != 1700005400 {
+		t.Errorf("exp got %d, want %d", exp.Unix(), 1700005400)
+	}
+
+	if jti, _ := claims.GetString("jti"); jti == "id-1" || jti == "" {
+		t.Errorf("jti got %s, want a new id", jti)
+	}
+	if sub, _ := claims.GetSubject(); sub != "subject" {
+		t.Errorf("sub got %s, want %s", sub, "subject")
+	}
+	if role, _ := claims.GetString("role"); role != "user" {
+		t.Errorf("role got %s, want %s", role, "user")
+	}
+
+	refreshedString, err := refreshed.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Parse(refreshedString, key); err != nil {
+		t.Fatal(err)
+	}
+
+	// the parsed token is not changed
+	claims, _ = parsed.GetClaims()
+	if role, _ := claims.GetString("role"); role != "admin" {
+		t.Errorf("role got %s, want %s", role, "admin")
+	}
+}
+
+func Test_Refresh_OtherSigner(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := SigningMethodHS256.New().WithClock(clock).Build().
+		WithHeader("kid", "key1").
+		IdentifiedBy("id-1").
+		RelatedTo("subject").
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := SigningMethodES256.New().WithClock(clock)
+
+	refreshed, err := s.Refresh(token, privateKey, RefreshOption{
+		ExpiresIn: time.Minute,
+		KeepID:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshedString, err := refreshed.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := s.Parse(refreshedString, &privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers, _ := parsed.GetHeaders()
+	if headers["alg"] != "ES256" {
+		t.Errorf("alg got %s, want %s", headers["alg"], "ES256")
+	}
+	// the HS256 key `kid` is not kept
+	if _, ok := headers["kid"]; ok {
+		t.Errorf("kid got %s, want no kid", headers["kid"])
+	}
+
+	claims, _ := parsed.GetClaims()
+
+	exp, _ := claims.GetExpirationTime()
+	if exp.Unix() != 1700000060 {
+		t.Errorf("exp got %d, want %d", exp.Unix(), 1700000060)
+	}
+	if jti, _ := claims.GetString("jti"); jti != "id-1" {
+		t.Errorf("jti got %s, want %s", jti, "id-1")
+	}
+}
+
+func Test_Refresh_KeyID(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := SigningMethodHS256.New().WithClock(clock).Build().
+		WithHeader("kid", "key1").
+		IssuedNow().
+		ExpiresIn(time.Hour).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the `kid` is set from the new key thumbprint
+	s := SigningMethodES256.New().WithClock(clock).WithKeyIDThumbprint(crypto.SHA256)
+
+	refreshed, err := s.Refresh(token, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumbprint, err := KeyThumbprint(&privateKey.PublicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers, _ := refreshed.GetHeaders()
+	if headers["kid"] != thumbprint {
+		t.Errorf("kid got %s, want %s", headers["kid"], thumbprint)
+	}
+
+	// the key set selects the new key by the `kid`
+	refreshedString, err := refreshed.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keySet := NewKeySet(
+		NewKeySetKey("key1", key),
+		NewKeySetKey(thumbprint, &privateKey.PublicKey),
+	)
+	if _, err := ParseWithKeySet(refreshedString, keySet, ParserOption{
+		Encoder: JWTEncoder,
+		Clock:   clock,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Refresh_LifetimeInvalid(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	s := SigningMethodHS256.New().WithClock(clock)
+
+	token, err := s.Build().
+		ExpiresIn(time.Hour).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Refresh(token, key)
+	if !errors.Is(err, ErrJWTRefreshLifetimeInvalid) {
+		t.Errorf("Refresh got %v, want %v", err, ErrJWTRefreshLifetimeInvalid)
+	}
+
+	refreshed, err := s.Refresh(token, key, RefreshOption{
+		ExpiresIn: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := refreshed.GetClaims()
+	exp, _ := claims.GetExpirationTime()
+	if exp.Unix() != 1700000060 {
+		t.Errorf("exp got %d, want %d", exp.Unix(), 1700000060)
+	}
+
+	// the token without `exp` is refreshed without `exp`
+	token, err = s.Build().RelatedTo("subject").GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err = s.Refresh(token, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ = refreshed.GetClaims()
+	if _, ok := claims["exp"]; ok {
+		t.Errorf("exp got %v, want no exp", claims["exp"])
+	}
+}
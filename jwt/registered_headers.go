@@ -10,6 +10,8 @@ type RegisteredHeaders struct {
 	KeyID string `json:"kid,omitempty"`
 	// content type
 	ContentType string `json:"cty,omitempty"`
+	// content encryption, used by JWE
+	Encryption string `json:"enc,omitempty"`
 }
 
 func (h RegisteredHeaders) GetType() (string, error) {
@@ -27,3 +29,7 @@ func (h RegisteredHeaders) GetKeyID() (string, error) {
 func (h RegisteredHeaders) GetContentType() (string, error) {
 	return h.ContentType, nil
 }
+
+func (h RegisteredHeaders) GetEncryption() (string, error) {
+	return h.Encryption, nil
+}
@@ -7,6 +7,12 @@ type registeredStdHeaders struct {
 	KeyID       string
 	ContentType string
 	Encryption  string
+	Critical    string
+	Compression string
+
+	EphemeralPublicKey string
+	AgreementPartyU    string
+	AgreementPartyV    string
 }
 
 // Defines "JSON Web Token Claims" struct
@@ -27,6 +33,12 @@ var RegisteredStdHeaders = registeredStdHeaders{
 	KeyID:       "kid",
 	ContentType: "cty",
 	Encryption:  "enc",
+	Critical:    "crit",
+	Compression: "zip",
+
+	EphemeralPublicKey: "epk",
+	AgreementPartyU:    "apu",
+	AgreementPartyV:    "apv",
 }
 
 // Defines the list of claims that are registered in the IANA "JSON Web Token Claims" registry
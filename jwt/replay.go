@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJWTTokenReplayed = errors.New("go-jwt: token has been used")
+)
+
+// ReplayCache remembers the used token ids, set it with the
+// ParserOption ReplayCache for the single-use tokens.
+type ReplayCache interface {
+	// Use marks the `jti` as used until the expiration time, it returns
+	// false when the `jti` has been used and it is not expired.
+	Use(id string, expiresAt time.Time) (bool, error)
+}
+
+// check the token `jti` with the replay cache, the token must have
+// the `jti` and `exp` claims, the `jti` is kept until `exp` and leeway
+func checkReplay(claims Claims, raw MapClaims, parserOpt ParserOption) error {
+	if parserOpt.ReplayCache == nil {
+		return nil
+	}
+
+	id, _ := raw["jti"].(string)
+	if id == "" {
+		return newClaimsValidationError(NewError("jti", ErrJWTTokenRequiredClaimMissing))
+	}
+
+	if _, ok := raw["exp"]; !ok {
+		return newClaimsValidationError(NewError("exp", ErrJWTTokenRequiredClaimMissing))
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return newClaimsValidationError(err)
+	}
+	if exp == nil {
+		return newClaimsValidationError(NewError("exp", ErrJWTTokenRequiredClaimMissing))
+	}
+
+	ok, err := parserOpt.ReplayCache.Use(id, exp.Add(parserOpt.Leeway))
+	if err != nil {
+		return newValidationError(err, ErrJWTTokenUnverifiable)
+	}
+	if !ok {
+		return newClaimsValidationError(ErrJWTTokenReplayed)
+	}
+
+	return nil
+}
+
+const (
+	// default shard count of the memory replay cache
+	DefaultReplayCacheShards = 32
+
+	// the memory replay cache shard cleanup interval
+	replayCleanupInterval = time.Minute
+)
+
+// MemoryReplayCache is a ReplayCache in memory, the ids are kept in
+// shards to reduce the lock contention, and removed after they expire.
+type MemoryReplayCache struct {
+	shards []*replayShard
+	clock  Clock
+}
+
+// a replay cache shard
+type replayShard struct {
+	mu          sync.Mutex
+	ids         map[string]time.Time
+	lastCleanup time.Time
+}
+
+// NewMemoryReplayCache returns a new MemoryReplayCache, the
+// DefaultReplayCacheShards is used when shards is not more than 0.
+func NewMemoryReplayCache(shards int) *MemoryReplayCache {
+	if shards <= 0 {
+		shards = DefaultReplayCacheShards
+	}
+
+	c := &MemoryReplayCache{
+		shards: make([]*replayShard, shards),
+	}
+	for i := range c.shards {
+		c.shards[i] = &replayShard{
+			ids: make(map[string]time.Time),
+		}
+	}
+
+	return c
+}
+
+// with the clock for the ids expiration, SystemClock is used when nil
+func (c *MemoryReplayCache) WithClock(clock Clock) *MemoryReplayCache {
+	c.clock = clock
+	return c
+}
+
+// Use implements the ReplayCache interface.
+func (c *MemoryReplayCache) Use(id string, expiresAt time.Time) (bool, error) {
+	now := clockNow(c.clock)
+	shard := c.shard(id)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.cleanup(now)
+
+	if usedUntil, ok := shard.ids[id]; ok && now.Before(usedUntil) {
+		return false, nil
+	}
+
+	shard.ids[id] = expiresAt
+	return true, nil
+}
+
+// Len returns the number of the ids.
+func (c *MemoryReplayCache) Len() int {
+	var n int
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		n += len(shard.ids)
+		shard.mu.Unlock()
+	}
+
+	return n
+}
+
+// return the shard of the id
+func (c *MemoryReplayCache) shard(id string) *replayShard {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// remove the expired ids
+func (s *replayShard) cleanup(now time.Time) {
+	if now.Sub(s.lastCleanup) < replayCleanupInterval {
+		return
+	}
+
+	s.lastCleanup = now
+
+	for id, expiresAt := range s.ids {
+		if !now.Before(expiresAt) {
+			delete(s.ids, id)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Parse_ReplayCache(t *testing.T) {
+	key := []byte("test-key")
+	clock := NewFrozenClock(time.Unix(1700000000, 0))
+
+	s := SigningMethodHS256.New().WithClock(clock)
+
+	token, err := s.Build().
+		WithRandomID().
+		ExpiresIn(time.Minute).
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenString, err := token.SignedString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewMemoryReplayCache(0).WithClock(clock)
+	opt := ParserOption{
+		ReplayCache: cache,
+	}
+
+	if _, err := s.Parse(tokenString, key, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Parse(tokenString, key, opt)
+	if !errors.Is(err, ErrJWTTokenReplayed) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenReplayed)
+	}
+	if !errors.Is(err, ErrJWTTokenInvalidClaims) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenInvalidClaims)
+	}
+
+	// the invalid tokens are not marked as used
+	token2, err := s.Build().
+		WithRandomID().
+		ExpiresIn(time.Minute).
+		IssuedBy("other").
+		GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenString2, _ := token2.SignedString()
+
+	_, err = s.Parse(tokenString2, key, ParserOption{
+		Issuer:      "issuer",
+		ReplayCache: cache,
+	})
+	if !errors.Is(err, ErrJWTTokenInvalidIssuer) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenInvalidIssuer)
+	}
+
+	if _, err := s.Parse(tokenString2, key, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	// the `jti` and `exp` claims are required
+	token3, err := s.Build().WithRandomID().GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenString3, _ := token3.SignedString()
+
+	_, err = s.Parse(tokenString3, key, opt)
+	if !errors.Is(err, ErrJWTTokenRequiredClaimMissing) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenRequiredClaimMissing)
+	}
+
+	_, _, err = ParseWithClaims[RegisteredClaims](tokenString, func(t *Token) ([]byte, error) {
+		return key, nil
+	}, ParserOption{
+		Encoder:     JWTEncoder,
+		Clock:       clock,
+		ReplayCache: cache,
+	})
+	if !errors.Is(err, ErrJWTTokenReplayed) {
+		t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenReplayed)
+	}
+}
+
+func Test_MemoryReplayCache(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := NewFrozenClock(now)
+
+	cache := NewMemoryReplayCache(4).WithClock(clock)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	used := map[string]int{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				id := fmt.Sprintf("id-%d", j)
+
+				ok, err := cache.Use(id, now.Add(time.Minute))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if ok {
+					mu.Lock()
+					used[id]++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(used) != 100 {
+		t.Errorf("used ids got %d, want %d", len(used), 100)
+	}
+	for id, n := range used {
+		if n != 1 {
+			t.Errorf("id %s used %d times, want 1", id, n)
+		}
+	}
+
+	// the expired ids can be used again, and are removed
+	clock.Advance(2 * time.Minute)
+
+	ok, err := cache.Use("id-1", now.Add(3*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Use expired id got %v, want %v", ok, true)
+	}
+
+	if cache.Len() >= 100 {
+		t.Errorf("Len got %d, want less than %d", cache.Len(), 100)
+	}
+}
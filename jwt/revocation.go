@@ -0,0 +1,190 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJWTTokenRevoked = errors.New("go-jwt: token has been revoked")
+)
+
+// RevocationStore is checked by the parser after the signature
+// is verified, set it with the ParserOption Revocation.
+type RevocationStore interface {
+	// IsRevoked returns true when the token `jti` or `sub` is revoked,
+	// or the token was issued before the `sub` cutoff time. The id,
+	// subject and issuedAt are zero values when the claims are not set.
+	IsRevoked(id, subject string, issuedAt time.Time) (bool, error)
+}
+
+// check the token claims with the revocation store
+func checkRevocation(claims Claims, raw MapClaims, parserOpt ParserOption) error {
+	if parserOpt.Revocation == nil {
+		return nil
+	}
+
+	id, _ := raw["jti"].(string)
+
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return newClaimsValidationError(err)
+	}
+
+	var issuedAt time.Time
+	if _, ok := raw["iat"]; ok {
+		iat, err := claims.GetIssuedAt()
+		if err != nil {
+			return newClaimsValidationError(err)
+		}
+
+		if iat != nil {
+			issuedAt = iat.Time
+		}
+	}
+
+	revoked, err := parserOpt.Revocation.IsRevoked(id, subject, issuedAt)
+	if err != nil {
+		return newValidationError(err, ErrJWTTokenUnverifiable)
+	}
+	if revoked {
+		return newClaimsValidationError(ErrJWTTokenRevoked)
+	}
+
+	return nil
+}
+
+// the memory revocation store cleanup interval
+const revocationCleanupInterval = time.Minute
+
+// MemoryRevocationStore is a RevocationStore in memory. The entries
+// are removed after their expiration time, which should be the max
+// expiration time of the revoked tokens, a zero time is never removed.
+type MemoryRevocationStore struct {
+	mu          sync.RWMutex
+	ids         map[string]time.Time
+	subjects    map[string]time.Time
+	cutoffs     map[string]revocationCutoff
+	clock       Clock
+	lastCleanup time.Time
+}
+
+// an issued-before cutoff of a subject
+type revocationCutoff struct {
+	issuedBefore time.Time
+	expiresAt    time.Time
+}
+
+// NewMemoryRevocationStore returns a new MemoryRevocationStore.
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		ids:      make(map[string]time.Time),
+		subjects: make(map[string]time.Time),
+		cutoffs:  make(map[string]revocationCutoff),
+	}
+}
+
+// with the clock for the entries expiration, SystemClock is used when nil
+func (s *MemoryRevocationStore) WithClock(clock Clock) *MemoryRevocationStore {
+	s.clock = clock
+	return s
+}
+
+// RevokeID revokes the token with the `jti` until the expiration time.
+func (s *MemoryRevocationStore) RevokeID(id string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanup()
+	s.ids[id] = expiresAt
+}
+
+// RevokeSubject revokes all the tokens of the `sub` until the expiration time.
+func (s *MemoryRevocationStore) RevokeSubject(subject string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanup()
+	s.subjects[subject] = expiresAt
+}
+
+// RevokeIssuedBefore revokes the tokens of the `sub` issued before the
+// cutoff time, and the tokens without `iat`, until the expiration time.
+func (s *MemoryRevocationStore) RevokeIssuedBefore(subject string, cutoff time.Time, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanup()
+	s.cutoffs[subject] = revocationCutoff{
+		issuedBefore: cutoff,
+		expiresAt:    expiresAt,
+	}
+}
+
+// IsRevoked implements the RevocationStore interface.
+func (s *MemoryRevocationStore) IsRevoked(id, subject string, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := clockNow(s.clock)
+
+	if id != "" {
+		if expiresAt, ok := s.ids[id]; ok && !revocationExpired(expiresAt, now) {
+			return true, nil
+		}
+	}
+
+	if subject != "" {
+		if expiresAt, ok := s.subjects[subject]; ok && !revocationExpired(expiresAt, now) {
+			return true, nil
+		}
+
+		if cutoff, ok := s.cutoffs[subject]; ok && !revocationExpired(cutoff.expiresAt, now) {
+			if issuedAt.IsZero() || issuedAt.Before(cutoff.issuedBefore) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Len returns the number of the entries.
+func (s *MemoryRevocationStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.ids) + len(s.subjects) + len(s.cutoffs)
+}
+
+// remove the expired entries
+func (s *MemoryRevocationStore) cleanup() {
+	now := clockNow(s.clock)
+	if now.Sub(s.lastCleanup) < revocationCleanupInterval {
+		return
+	}
+
+	s.lastCleanup = now
+
+	for id, expiresAt := range s.ids {
+		if revocationExpired(expiresAt, now) {
+			delete(s.ids, id)
+		}
+	}
+	for subject, expiresAt := range s.subjects {
+		if revocationExpired(expiresAt, now) {
+			delete(s.subjects, subject)
+		}
+	}
+	for subject, cutoff := range s.cutoffs {
+		if revocationExpired(cutoff.expiresAt, now) {
+			delete(s.cutoffs, subject)
+		}
+	}
+}
+
+// return true when the entry is expired, the zero time is never expired
+func revocationExpired(expiresAt time.Time, now time.Time) bool {
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
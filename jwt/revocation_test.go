@@ -0,0 +1,172 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Parse_Revocation(t *testing.T) {
+	key := []byte("test-key")
+	now := time.Unix(1700000000, 0)
+	clock := NewFrozenClock(now)
+
+	s := SigningMethodHS256.New().WithClock(clock)
+
+	newToken := func(id string, sub string, iat time.Time) string {
+		token, err := s.Build().
+			IdentifiedBy(id).
+			RelatedTo(sub).
+			IssuedAt(NewNumericDate(iat)).
+			ExpiresAt(NewNumericDate(iat.Add(time.Hour))).
+			GetToken(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tokenString, err := token.SignedString()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return tokenString
+	}
+
+	store := NewMemoryRevocationStore().WithClock(clock)
+	opt := ParserOption{
+		Revocation: store,
+	}
+
+	token1 := newToken("id1", "user1", now)
+	token2 := newToken("id2", "user2", now.Add(-time.Minute))
+	token3 := newToken("id3", "user2", now)
+	token4 := newToken("id4", "user3", now)
+
+	for _, tokenString := range []string{token1, token2, token3, token4} {
+		if _, err := s.Parse(tokenString, key, opt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store.RevokeID("id1", now.Add(time.Hour))
+	store.RevokeIssuedBefore("user2", now.Add(-time.Second), now.Add(time.Hour))
+	store.RevokeSubject("user3", time.Time{})
+
+	for _, tokenString := range []string{token1, token2, token4} {
+		_, err := s.Parse(tokenString, key, opt)
+		if !errors.Is(err, ErrJWTTokenRevoked) {
+			t.Errorf("Parse got %v, want %v", err, ErrJWTTokenRevoked)
+		}
+		if !errors.Is(err, ErrJWTTokenInvalidClaims) {
+			t.Errorf("Parse got %v, want %v", err, ErrJWTTokenInvalidClaims)
+		}
+	}
+
+	// issued after the cutoff
+	if _, err := s.Parse(token3, key, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	// the revocation is checked when skips the claims validation
+	_, err := Parse(token1, func(t *Token) ([]byte, error) {
+		return key, nil
+	}, ParserOption{
+		Encoder:              JWTEncoder,
+		SkipClaimsValidation: true,
+		Revocation:           store,
+	})
+	if !errors.Is(err, ErrJWTTokenRevoked) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenRevoked)
+	}
+
+	_, _, err = ParseWithClaims[*RegisteredClaims](token2, func(t *Token) ([]byte, error) {
+		return key, nil
+	}, ParserOption{
+		Encoder:    JWTEncoder,
+		Clock:      clock,
+		Revocation: store,
+	})
+	if !errors.Is(err, ErrJWTTokenRevoked) {
+		t.Errorf("ParseWithClaims got %v, want %v", err, ErrJWTTokenRevoked)
+	}
+
+	// the tokens without `iat` and `sub`
+	token5, err := s.Build().IdentifiedBy("id5").GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenString5, _ := token5.SignedString()
+	if _, err := s.Parse(tokenString5, key, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	store.RevokeID("id5", time.Time{})
+	if _, err := s.Parse(tokenString5, key, opt); !errors.Is(err, ErrJWTTokenRevoked) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenRevoked)
+	}
+
+	errStore := errors.New("store fail")
+
+	_, err = s.Parse(token3, key, ParserOption{
+		Revocation: revocationStoreFunc(func(id, subject string, issuedAt time.Time) (bool, error) {
+			return false, errStore
+		}),
+	})
+	if !errors.Is(err, errStore) || !errors.Is(err, ErrJWTTokenUnverifiable) {
+		t.Errorf("Parse got %v, want %v", err, errStore)
+	}
+}
+
+type revocationStoreFunc func(id, subject string, issuedAt time.Time) (bool, error)
+
+func (f revocationStoreFunc) IsRevoked(id, subject string, issuedAt time.Time) (bool, error) {
+	return f(id, subject, issuedAt)
+}
+
+func Test_MemoryRevocationStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := NewFrozenClock(now)
+
+	store := NewMemoryRevocationStore().WithClock(clock)
+	store.RevokeID("id1", now.Add(time.Hour))
+	store.RevokeSubject("user1", time.Time{})
+	store.RevokeIssuedBefore("user2", now, now.Add(time.Hour))
+
+	checks := []struct {
+		id       string
+		subject  string
+		issuedAt time.Time
+		want     bool
+	}{
+		{"id1", "", time.Time{}, true},
+		{"id2", "user1", now, true},
+		{"id2", "user2", now.Add(-time.Second), true},
+		{"id2", "user2", time.Time{}, true},
+		{"id2", "user2", now, false},
+		{"id2", "user3", now, false},
+	}
+
+	for _, c := range checks {
+		got, err := store.IsRevoked(c.id, c.subject, c.issuedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("IsRevoked(%s, %s) got %v, want %v", c.id, c.subject, got, c.want)
+		}
+	}
+
+	// the expired entries are not used, and removed when revokes
+	clock.Advance(time.Hour)
+
+	if got, _ := store.IsRevoked("id1", "user2", time.Time{}); got {
+		t.Errorf("IsRevoked expired got %v, want %v", got, false)
+	}
+
+	store.RevokeID("id3", now.Add(2*time.Hour))
+
+	if store.Len() != 2 {
+		t.Errorf("Len got %d, want %d", store.Len(), 2)
+	}
+}
@@ -50,6 +50,11 @@ func (s *SignRSA) SignLength() int {
 	return MaxModulusLen
 }
 
+// GenerateKey returns a DefaultRSAKeySize bits key.
+func (s *SignRSA) GenerateKey() (*rsa.PrivateKey, error) {
+	return GenerateRSAKey(DefaultRSAKeySize)
+}
+
 // Sign implements token signing for the Signer.
 func (s *SignRSA) Sign(msg []byte, key *rsa.PrivateKey) ([]byte, error) {
 	hasher := s.Hash.New()
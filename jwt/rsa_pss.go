@@ -73,6 +73,11 @@ func (s *SignRSAPSS) SignLength() int {
 	return MaxModulusLen
 }
 
+// GenerateKey returns a DefaultRSAKeySize bits key.
+func (s *SignRSAPSS) GenerateKey() (*rsa.PrivateKey, error) {
+	return GenerateRSAKey(DefaultRSAKeySize)
+}
+
 // Sign implements token signing for the Signer.
 func (s *SignRSAPSS) Sign(msg []byte, key *rsa.PrivateKey) ([]byte, error) {
 	hasher := s.Hash.New()
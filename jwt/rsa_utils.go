@@ -1,19 +1,85 @@
 package jwt
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"errors"
 )
 
 var (
-	ErrNotRSAPrivateKey = errors.New("go-jwt: key is not a valid RSA private key")
-	ErrNotRSAPublicKey  = errors.New("go-jwt: key is not a valid RSA public key")
+	ErrNotRSAPrivateKey   = errors.New("go-jwt: key is not a valid RSA private key")
+	ErrNotRSAPublicKey    = errors.New("go-jwt: key is not a valid RSA public key")
+	ErrRSAKeySizeTooSmall = errors.New("go-jwt: RSA key size too small")
 )
 
+const (
+	// min RSA key size, see RFC 7518 section 3.3 and 3.5
+	MinRSAKeySize = 2048
+
+	// default RSA key size of the generated keys
+	DefaultRSAKeySize = 2048
+)
+
+// GenerateRSAKey returns a RSA key, the bits must be at least MinRSAKeySize
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	if bits < MinRSAKeySize {
+		return nil, ErrRSAKeySizeTooSmall
+	}
+
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// MarshalRSAPrivateKeyToPKCS1PEM returns the PEM encoded PKCS1 private key
+func MarshalRSAPrivateKeyToPKCS1PEM(key *rsa.PrivateKey) ([]byte, error) {
+	der, err := MarshalRSAPrivateKeyToPKCS1Der(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodePEM("RSA PRIVATE KEY", der), nil
+}
+
+// MarshalRSAPublicKeyToPKCS1PEM returns the PEM encoded PKCS1 public key
+func MarshalRSAPublicKeyToPKCS1PEM(key *rsa.PublicKey) ([]byte, error) {
+	der, err := MarshalRSAPublicKeyToPKCS1Der(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodePEM("RSA PUBLIC KEY", der), nil
+}
+
+func MarshalRSAPrivateKeyToPKCS1Der(key *rsa.PrivateKey) ([]byte, error) {
+	if key == nil {
+		return nil, ErrNotRSAPrivateKey
+	}
+
+	return x509.MarshalPKCS1PrivateKey(key), nil
+}
+
+func MarshalRSAPublicKeyToPKCS1Der(key *rsa.PublicKey) ([]byte, error) {
+	if key == nil {
+		return nil, ErrNotRSAPublicKey
+	}
+
+	return x509.MarshalPKCS1PublicKey(key), nil
+}
+
 // ParseRSAPrivateKeyFromPEM parses a PEM encoded PKCS1 or PKCS8 private key
 func ParseRSAPrivateKeyFromPEM(key []byte) (*rsa.PrivateKey, error) {
-	der, err := ParsePEM(key)
+	der, err := parsePrivateKeyPEM(key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRSAPrivateKeyFromDer(der)
+}
+
+// ParseRSAPrivateKeyFromPEMWithPassword parses a PEM encoded private key,
+// the ENCRYPTED PRIVATE KEY block is decrypted with the password
+func ParseRSAPrivateKeyFromPEMWithPassword(key []byte, password []byte) (*rsa.PrivateKey, error) {
+	der, err := parsePrivateKeyPEM(key, password)
 	if err != nil {
 		return nil, err
 	}
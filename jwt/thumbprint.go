@@ -0,0 +1,44 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+
+	"github.com/deatil/go-jwt/jwk"
+)
+
+var ErrJWTThumbprintSymmetricKey = errors.New("go-jwt: thumbprint kid of a symmetric key is not allowed")
+
+// KeyThumbprint returns the base64url encoded JWK thumbprint of the key, see RFC 7638.
+// The key can be a RSA, ECDSA, Ed25519 or HMAC key, a private key has
+// the same thumbprint as its public key. The thumbprint of a HMAC key is
+// computed from the secret, so a weak secret can be guessed from it,
+// and it is not used for the `kid` header.
+func KeyThumbprint(key any, hash crypto.Hash) (string, error) {
+	k, err := jwk.New(key)
+	if err != nil {
+		return "", err
+	}
+
+	return k.ThumbprintString(hash)
+}
+
+// set the `kid` header from the key thumbprint when the header not has `kid`,
+// the HMAC keys return ErrJWTThumbprintSymmetricKey
+func setThumbprintKeyID(headers map[string]any, key any, hash crypto.Hash) error {
+	if kid, ok := headers[RegisteredStdHeaders.KeyID]; ok && kid != "" {
+		return nil
+	}
+
+	if _, ok := key.([]byte); ok {
+		return ErrJWTThumbprintSymmetricKey
+	}
+
+	kid, err := KeyThumbprint(key, hash)
+	if err != nil {
+		return err
+	}
+
+	headers[RegisteredStdHeaders.KeyID] = kid
+	return nil
+}
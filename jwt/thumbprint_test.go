@@ -0,0 +1,179 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func Test_KeyThumbprint(t *testing.T) {
+	var prikey = "MC4CAQAwBQYDK2VwBCIEIE7YvvGJzvKQ3uZOQ6qAPkRsK7nkpmjPOaqsZKqrFQMw"
+
+	privateKey, err := ParseEdPrivateKeyFromDer(fromBase64(prikey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid1, err := KeyThumbprint(privateKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid2, err := KeyThumbprint(privateKey.Public(), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kid1 != kid2 {
+		t.Errorf("KeyThumbprint got %s, want %s", kid1, kid2)
+	}
+
+	_, err = KeyThumbprint("test", crypto.SHA256)
+	if err == nil {
+		t.Error("KeyThumbprint should return error")
+	}
+}
+
+func Test_JWT_WithKeyIDThumbprint(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := &privateKey.PublicKey
+
+	check, err := KeyThumbprint(publicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := map[string]string{
+		"sub": "foo",
+	}
+
+	s := SigningMethodES256.New().WithKeyIDThumbprint(crypto.SHA256)
+
+	tokenString, err := s.Sign(claims, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := s.New().Parse(tokenString, publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := parsed.GetHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid, _ := header.GetKeyID()
+	if kid != check {
+		t.Errorf("GetKeyID got %s, want %s", kid, check)
+	}
+	typ, _ := header.GetType()
+	if typ != "JWT" {
+		t.Errorf("GetType got %s, want %s", typ, "JWT")
+	}
+
+	// a `kid` header is not overwritten
+	tokenString, err = s.SignWithHeader(RegisteredHeaders{
+		Type:      "JWT",
+		Algorithm: "ES256",
+		KeyID:     "my-key",
+	}, claims, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err = GetTokenHeader(tokenString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid, _ = header.GetKeyID()
+	if kid != "my-key" {
+		t.Errorf("GetKeyID got %s, want %s", kid, "my-key")
+	}
+}
+
+func Test_Builder_WithKeyIDThumbprint(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check, err := KeyThumbprint(privateKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := SigningMethodEdDSA.New().WithKeyIDThumbprint(crypto.SHA256)
+
+	token, err := s.Build().
+		RelatedTo("subject").
+		GetToken(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := token.GetHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid, _ := header.GetKeyID()
+	if kid != check {
+		t.Errorf("GetKeyID got %s, want %s", kid, check)
+	}
+
+	token, err = SigningMethodEdDSA.New().Build().
+		WithKeyIDThumbprint(crypto.SHA256).
+		WithHeader("kid", "my-key").
+		GetToken(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, _ = token.GetHeader()
+	kid, _ = header.GetKeyID()
+	if kid != "my-key" {
+		t.Errorf("GetKeyID got %s, want %s", kid, "my-key")
+	}
+}
+
+func Test_WithKeyIDThumbprint_Hmac(t *testing.T) {
+	key := []byte("secret")
+	claims := map[string]string{
+		"sub": "foo",
+	}
+
+	s := SigningMethodHS256.New().WithKeyIDThumbprint(crypto.SHA256)
+
+	_, err := s.Sign(claims, key)
+	if !errors.Is(err, ErrJWTThumbprintSymmetricKey) {
+		t.Errorf("Sign got %v, want %v", err, ErrJWTThumbprintSymmetricKey)
+	}
+
+	_, err = s.Build().RelatedTo("foo").GetToken(key)
+	if !errors.Is(err, ErrJWTThumbprintSymmetricKey) {
+		t.Errorf("GetToken got %v, want %v", err, ErrJWTThumbprintSymmetricKey)
+	}
+
+	// a `kid` header is used as it is
+	token, err := s.Build().WithHeader("kid", "my-key").GetToken(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, _ := token.GetHeader()
+	kid, _ := header.GetKeyID()
+	if kid != "my-key" {
+		t.Errorf("GetKeyID got %s, want %s", kid, "my-key")
+	}
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"strings"
 )
 
@@ -97,8 +98,16 @@ func (t *Token) signing(needSign bool) (string, error) {
 	return buf.String(), nil
 }
 
-// Parse token string and returns the parsed token.
-func (t *Token) Parse(tokenString string) {
+// Parse token string, the parts are set even when
+// the base64 decode fail, and the errors are returned.
+func (t *Token) Parse(tokenString string) error {
+	headerErr, claimsErr, signatureErr := t.parseParts(tokenString)
+
+	return errors.Join(headerErr, claimsErr, signatureErr)
+}
+
+// parse token string and returns each part base64 decode error
+func (t *Token) parseParts(tokenString string) (headerErr, claimsErr, signatureErr error) {
 	t.raw = tokenString
 	t.header = []byte{}
 	t.claims = []byte{}
@@ -108,15 +117,26 @@ func (t *Token) Parse(tokenString string) {
 		return
 	}
 
+	var err error
+
 	list := strings.Split(tokenString, tokenDelimiter)
 	if len(list) > 0 {
-		t.header, _ = t.encoder.Base64URLDecode(list[0])
+		t.header, err = t.encoder.Base64URLDecode(list[0])
+		if err != nil {
+			headerErr = NewError("header base64 decode fail", ErrJWTTokenMalformed, err)
+		}
 	}
 	if len(list) > 1 {
-		t.claims, _ = t.encoder.Base64URLDecode(list[1])
+		t.claims, err = t.encoder.Base64URLDecode(list[1])
+		if err != nil {
+			claimsErr = NewError("claims base64 decode fail", ErrJWTTokenMalformed, err)
+		}
 	}
 	if len(list) > 2 {
-		t.signature, _ = t.encoder.Base64URLDecode(list[2])
+		t.signature, err = t.encoder.Base64URLDecode(list[2])
+		if err != nil {
+			signatureErr = NewError("signature base64 decode fail", ErrJWTTokenMalformed, err)
+		}
 	}
 
 	if len(list) > 1 {
@@ -124,6 +144,8 @@ func (t *Token) Parse(tokenString string) {
 	} else {
 		t.msg = tokenString
 	}
+
+	return
 }
 
 // return token raw
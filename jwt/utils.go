@@ -1,11 +1,78 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/x509"
 	"encoding/pem"
 	"errors"
 )
 
-var ErrPEMInvalid = errors.New("go-jwt: PEM parse invalid")
+var (
+	ErrPEMInvalid         = errors.New("go-jwt: PEM parse invalid")
+	ErrKeyGenerateInvalid = errors.New("go-jwt: key can not be generated for the alg")
+)
+
+// GenerateKey returns a new signing key of the registered alg, the key is
+// []byte, *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey.
+func GenerateKey(alg string) (any, error) {
+	switch m := GetSigningMethod(alg).(type) {
+	case *SignHmac:
+		return m.GenerateKey()
+	case *SignBlake2b:
+		return m.GenerateKey()
+	case *SignRSA:
+		return m.GenerateKey()
+	case *SignRSAPSS:
+		return m.GenerateKey()
+	case *SignECDSA:
+		return m.GenerateKey()
+	case *SignEdDSA:
+		return m.GenerateKey()
+	}
+
+	return nil, NewError(alg, ErrKeyGenerateInvalid)
+}
+
+// MarshalPKCS8PrivateKeyToPEM returns the PEM encoded PKCS8 private key
+func MarshalPKCS8PrivateKeyToPEM(key any) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKeyToDer(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodePEM("PRIVATE KEY", der), nil
+}
+
+// MarshalPublicKeyToPEM returns the PEM encoded SPKI (PKIX) public key,
+// the public key of a private key is used.
+func MarshalPublicKeyToPEM(key any) ([]byte, error) {
+	der, err := MarshalPublicKeyToDer(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodePEM("PUBLIC KEY", der), nil
+}
+
+func MarshalPKCS8PrivateKeyToDer(key any) ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(key)
+}
+
+func MarshalPublicKeyToDer(key any) ([]byte, error) {
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+
+	return x509.MarshalPKIXPublicKey(key)
+}
+
+// EncodePEM returns the PEM block of the der bytes
+func EncodePEM(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  blockType,
+		Bytes: der,
+	})
+}
 
 // parse PEM string and return der bytes
 func ParsePEM(data []byte) ([]byte, error) {
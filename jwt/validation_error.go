@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// the ValidationError categories
+var (
+	ErrJWTTokenMalformed     = errors.New("go-jwt: token is malformed")
+	ErrJWTTokenUnverifiable  = errors.New("go-jwt: token is unverifiable")
+	ErrJWTTokenInvalidClaims = errors.New("go-jwt: token has invalid claims")
+)
+
+// the claims ValidationError categories, they are added
+// with the ErrJWTTokenInvalidClaims category
+var claimsCategories = []error{
+	ErrJWTTokenExpired,
+	ErrJWTTokenNotValidYet,
+	ErrJWTTokenUsedBeforeIssued,
+	ErrJWTTokenInvalidIssuer,
+	ErrJWTTokenInvalidAudience,
+	ErrJWTTokenInvalidSubject,
+	ErrJWTTokenRequiredClaimMissing,
+	ErrJWTTokenRevoked,
+	ErrJWTTokenReplayed,
+}
+
+// ValidationError is returned by the parse functions. The Categories are
+// the failure kinds, like ErrJWTTokenMalformed or ErrJWTTokenExpired, and
+// the Err is the error cause. Both can be checked with errors.Is and errors.As.
+// A claims failure has the ErrJWTTokenInvalidClaims category, and the
+// ErrJWTTokenExpired, ErrJWTTokenNotValidYet, ErrJWTTokenUsedBeforeIssued,
+// ErrJWTTokenInvalidIssuer, ErrJWTTokenInvalidAudience,
+// ErrJWTTokenInvalidSubject, ErrJWTTokenRequiredClaimMissing,
+// ErrJWTTokenRevoked or ErrJWTTokenReplayed category of the failed claim.
+type ValidationError struct {
+	// the failure categories
+	Categories []error
+
+	// the error cause, used as the error message
+	Err error
+
+	// more causes, like the signing method verify error
+	Causes []error
+}
+
+// newValidationError returns a ValidationError with the cause and categories.
+// The categories are added when the cause is a ValidationError.
+func newValidationError(err error, categories ...error) *ValidationError {
+	if ve, ok := err.(*ValidationError); ok {
+		for _, c := range categories {
+			if !slices.Contains(ve.Categories, c) {
+				ve.Categories = append(ve.Categories, c)
+			}
+		}
+
+		return ve
+	}
+
+	return &ValidationError{
+		Categories: categories,
+		Err:        err,
+	}
+}
+
+// newClaimsValidationError returns a ValidationError of the claims
+// validation error, with the categories of the failed claims
+func newClaimsValidationError(err error) *ValidationError {
+	categories := []error{ErrJWTTokenInvalidClaims}
+	for _, c := range claimsCategories {
+		if errors.Is(err, c) {
+			categories = append(categories, c)
+		}
+	}
+
+	return newValidationError(err, categories...)
+}
+
+// withCauses adds more causes, nil causes are ignored
+func (e *ValidationError) withCauses(causes ...error) *ValidationError {
+	for _, c := range causes {
+		if c != nil {
+			e.Causes = append(e.Causes, c)
+		}
+	}
+
+	return e
+}
+
+// Error returns the cause message, or the categories message
+// when the cause is nil.
+func (e *ValidationError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	msgs := make([]string, 0, len(e.Categories))
+	for _, c := range e.Categories {
+		msgs = append(msgs, c.Error())
+	}
+
+	return strings.Join(msgs, ", ")
+}
+
+// Unwrap returns the categories, the cause and the more causes.
+func (e *ValidationError) Unwrap() []error {
+	errs := slices.Clone(e.Categories)
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+
+	return append(errs, e.Causes...)
+}
+
+// Has returns true when the error has the category.
+func (e *ValidationError) Has(category error) bool {
+	return slices.Contains(e.Categories, category)
+}
@@ -0,0 +1,375 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_ValidationError(t *testing.T) {
+	key := []byte("test-key")
+
+	tokenString, err := SigningMethodHS256.Sign(map[string]any{
+		"sub": "foo",
+		"nbf": time.Now().Add(-time.Hour).Unix(),
+		"exp": time.Now().Add(-time.Minute).Unix(),
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyErr := errors.New("key not found")
+
+	tests := []struct {
+		name     string
+		parse    func() error
+		category error
+		cause    error
+	}{
+		{
+			name: "malformed",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse("foo", key)
+				return err
+			},
+			category: ErrJWTTokenMalformed,
+			cause:    ErrJWTTokenInvalid,
+		},
+		{
+			name: "malformed base64",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse("e$.e30.", key)
+				return err
+			},
+			category: ErrJWTTokenMalformed,
+			cause:    ErrJWTTokenMalformed,
+		},
+		{
+			name: "unverifiable",
+			parse: func() error {
+				_, err := Parse(tokenString, func(t *Token) ([]byte, error) {
+					return nil, keyErr
+				})
+				return err
+			},
+			category: ErrJWTTokenUnverifiable,
+			cause:    keyErr,
+		},
+		{
+			name: "algo invalid",
+			parse: func() error {
+				_, err := SigningMethodHS384.Parse(tokenString, key)
+				return err
+			},
+			category: ErrJWTTokenUnverifiable,
+			cause:    ErrJWTAlgoInvalid,
+		},
+		{
+			name: "signature invalid",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString, []byte("other-key"))
+				return err
+			},
+			category: ErrJWTTokenSignatureInvalid,
+			cause:    ErrSignHmacVerifyFail,
+		},
+		{
+			name: "signature base64 invalid",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString+"$", key)
+				return err
+			},
+			category: ErrJWTTokenSignatureInvalid,
+			cause:    ErrJWTTokenMalformed,
+		},
+		{
+			name: "key set signature invalid",
+			parse: func() error {
+				_, err := ParseWithKeySet(tokenString, NewKeySet(NewKeySetKey("", []byte("other-key"))))
+				return err
+			},
+			category: ErrJWTTokenSignatureInvalid,
+			cause:    ErrSignHmacVerifyFail,
+		},
+		{
+			name: "expired",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString, key)
+				return err
+			},
+			category: ErrJWTTokenInvalidClaims,
+			cause:    ErrJWTTokenExpired,
+		},
+		{
+			name: "expired category",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString, key)
+				return err
+			},
+			category: ErrJWTTokenExpired,
+			cause:    ErrJWTTokenExpired,
+		},
+		{
+			name: "not valid yet",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString, key, ParserOption{
+					Encoder: JWTEncoder,
+					Clock:   NewFrozenClock(time.Unix(0, 0)),
+				})
+				return err
+			},
+			category: ErrJWTTokenNotValidYet,
+			cause:    ErrJWTTokenNotValidYet,
+		},
+		{
+			name: "audience",
+			parse: func() error {
+				_, _, err := ParseWithClaims[RegisteredClaims](tokenString, func(t *Token) ([]byte, error) {
+					return key, nil
+				}, ParserOption{
+					Encoder:  JWTEncoder,
+					Audience: []string{"bar"},
+					Leeway:   time.Hour,
+				})
+				return err
+			},
+			category: ErrJWTTokenInvalidAudience,
+			cause:    ErrJWTTokenInvalidAudience,
+		},
+		{
+			name: "subject",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString, key, ParserOption{
+					Subject: "bar",
+					Leeway:  time.Hour,
+				})
+				return err
+			},
+			category: ErrJWTTokenInvalidSubject,
+			cause:    ErrJWTTokenInvalidSubject,
+		},
+		{
+			name: "required claim",
+			parse: func() error {
+				_, err := SigningMethodHS256.Parse(tokenString, key, ParserOption{
+					RequiredClaims: []string{"jti"},
+					Leeway:         time.Hour,
+				})
+				return err
+			},
+			category: ErrJWTTokenRequiredClaimMissing,
+			cause:    ErrJWTTokenRequiredClaimMissing,
+		},
+		{
+			name: "revoked",
+			parse: func() error {
+				store := NewMemoryRevocationStore()
+				store.RevokeSubject("foo", time.Now().Add(time.Hour))
+
+				_, err := SigningMethodHS256.Parse(tokenString, key, ParserOption{
+					Revocation: store,
+					Leeway:     time.Hour,
+				})
+				return err
+			},
+			category: ErrJWTTokenRevoked,
+			cause:    ErrJWTTokenRevoked,
+		},
+		{
+			name: "replayed",
+			parse: func() error {
+				tokenString, err := SigningMethodHS256.Sign(map[string]any{
+					"jti": "id1",
+					"exp": time.Now().Add(time.Minute).Unix(),
+				}, key)
+				if err != nil {
+					return err
+				}
+
+				opt := ParserOption{
+					ReplayCache: NewMemoryReplayCache(1),
+				}
+
+				if _, err := SigningMethodHS256.Parse(tokenString, key, opt); err != nil {
+					return err
+				}
+
+				_, err = SigningMethodHS256.Parse(tokenString, key, opt)
+				return err
+			},
+			category: ErrJWTTokenReplayed,
+			cause:    ErrJWTTokenReplayed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse()
+			if err == nil {
+				t.Fatal("Parse should return error")
+			}
+
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("Parse error got %T, want *ValidationError", err)
+			}
+
+			if !ve.Has(tt.category) {
+				t.Errorf("Has got false, want %s", tt.category)
+			}
+			if !errors.Is(err, tt.category) {
+				t.Errorf("errors.Is got false, want %s", tt.category)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("errors.Is got false, want %s", tt.cause)
+			}
+		})
+	}
+}
+
+func Test_ValidationError_Decrypt(t *testing.T) {
+	key := []byte("1234567890123456")
+
+	e := NewJWE[[]byte, []byte](KeyManagementA128KW, ContentEncryptionA128GCM, JWTEncoder).New()
+
+	tokenString, err := e.Encrypt(map[string]string{"sub": "foo"}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		parse    func() error
+		category error
+		cause    error
+	}{
+		{
+			name: "malformed",
+			parse: func() error {
+				_, err := Decrypt[[]byte]("e$.e30", func(t *EncryptedToken) ([]byte, error) {
+					return key, nil
+				})
+				return err
+			},
+			category: ErrJWTTokenMalformed,
+			cause:    ErrJWTTokenMalformed,
+		},
+		{
+			name: "unverifiable",
+			parse: func() error {
+				_, err := Decrypt[[]byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+					return key, nil
+				}, ParserOption{
+					Encoder:      JWTEncoder,
+					ValidMethods: []string{"A256KW"},
+				})
+				return err
+			},
+			category: ErrJWTTokenUnverifiable,
+			cause:    ErrJWEKeyManagementInvalid,
+		},
+		{
+			name: "decrypt fail",
+			parse: func() error {
+				_, err := Decrypt[[]byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+					return []byte("6543210987654321"), nil
+				})
+				return err
+			},
+			category: ErrJWTTokenSignatureInvalid,
+			cause:    ErrJWEDecryptFail,
+		},
+		{
+			name: "nested content type",
+			parse: func() error {
+				_, err := ParseNested[[]byte, []byte](tokenString, func(t *EncryptedToken) ([]byte, error) {
+					return key, nil
+				}, func(t *Token) ([]byte, error) {
+					return []byte("test-key"), nil
+				})
+				return err
+			},
+			category: ErrJWTTokenMalformed,
+			cause:    ErrJWENestedContentTypeInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse()
+
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("Decrypt error got %T, want *ValidationError", err)
+			}
+
+			if !ve.Has(tt.category) {
+				t.Errorf("Has got false, want %s", tt.category)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("errors.Is got false, want %s", tt.cause)
+			}
+		})
+	}
+}
+
+func Test_ValidationError_ClaimsCategories(t *testing.T) {
+	err := newClaimsValidationError(NewError("exp", ErrJWTTokenExpired))
+
+	if !err.Has(ErrJWTTokenInvalidClaims) {
+		t.Errorf("Has got false, want %s", ErrJWTTokenInvalidClaims)
+	}
+	if !err.Has(ErrJWTTokenExpired) {
+		t.Errorf("Has got false, want %s", ErrJWTTokenExpired)
+	}
+	if err.Has(ErrJWTTokenInvalidAudience) {
+		t.Errorf("Has got true, want no %s", ErrJWTTokenInvalidAudience)
+	}
+}
+
+func Test_ValidationError_Error(t *testing.T) {
+	err := newValidationError(ErrJWTVerifyFail, ErrJWTTokenSignatureInvalid).
+		withCauses(nil, ErrSignHmacVerifyFail)
+
+	check := "go-jwt: Verify fail"
+	if err.Error() != check {
+		t.Errorf("Error got %s, want %s", err.Error(), check)
+	}
+	if len(err.Causes) != 1 {
+		t.Errorf("Causes got %d, want %d", len(err.Causes), 1)
+	}
+
+	err2 := newValidationError(err, ErrJWTTokenMalformed, ErrJWTTokenSignatureInvalid)
+	if err2 != err {
+		t.Error("newValidationError should return the ValidationError")
+	}
+	if len(err2.Categories) != 2 {
+		t.Errorf("Categories got %d, want %d", len(err2.Categories), 2)
+	}
+
+	err3 := &ValidationError{
+		Categories: []error{ErrJWTTokenMalformed, ErrJWTTokenUnverifiable},
+	}
+
+	check3 := "go-jwt: token is malformed, go-jwt: token is unverifiable"
+	if err3.Error() != check3 {
+		t.Errorf("Error got %s, want %s", err3.Error(), check3)
+	}
+}
+
+func Test_Token_Parse_Base64Error(t *testing.T) {
+	token := NewToken(JWTEncoder)
+
+	err := token.Parse("e30.e$.e30")
+	if !errors.Is(err, ErrJWTTokenMalformed) {
+		t.Errorf("Parse got %v, want %v", err, ErrJWTTokenMalformed)
+	}
+
+	if string(token.GetHeaderRaw()) != "{}" {
+		t.Errorf("GetHeaderRaw got %s, want %s", token.GetHeaderRaw(), "{}")
+	}
+
+	if err := token.Parse("e30.e30.e30"); err != nil {
+		t.Fatal(err)
+	}
+}
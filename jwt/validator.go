@@ -1,9 +1,21 @@
 package jwt
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // jwt token validator
 type Validator struct {
+	token  *Token
 	claims MapClaims
-	leeway int64
+	leeway time.Duration
+
+	// leeway for the claims, used before the validator leeway
+	claimLeeways map[string]time.Duration
+
+	// the clock used by the Now checks, SystemClock is used when nil
+	clock Clock
 }
 
 func NewValidator(token *Token) (*Validator, error) {
@@ -13,16 +25,49 @@ func NewValidator(token *Token) (*Validator, error) {
 	}
 
 	return &Validator{
-		claims: claims,
-		leeway: 0,
+		token:        token,
+		claims:       claims,
+		leeway:       0,
+		claimLeeways: make(map[string]time.Duration),
 	}, nil
 }
 
+// with the leeway seconds for the time claims
 func (v *Validator) WithLeeway(leeway int64) *Validator {
+	v.leeway = time.Duration(leeway) * time.Second
+	return v
+}
+
+// with the leeway for the time claims
+func (v *Validator) WithLeewayDuration(leeway time.Duration) *Validator {
 	v.leeway = leeway
 	return v
 }
 
+// with the leeway for the claim, like `exp`, `nbf` or `iat`
+func (v *Validator) WithClaimLeeway(name string, leeway time.Duration) *Validator {
+	v.claimLeeways[name] = leeway
+	return v
+}
+
+// with the clock used by the Now checks
+func (v *Validator) WithClock(clock Clock) *Validator {
+	v.clock = clock
+	return v
+}
+
+// Validate validates the token with all the constraints,
+// and returns all the violations.
+func (v *Validator) Validate(constraints ...Constraint) error {
+	return Validate(v.token, constraints...)
+}
+
+// Assert validates the token with the constraints,
+// and returns the first violation.
+func (v *Validator) Assert(constraints ...Constraint) error {
+	return Assert(v.token, constraints...)
+}
+
 func (v *Validator) IsPermittedFor(audiences []string) bool {
 	getAudiences, err := v.claims.GetAudience()
 	if err != nil {
@@ -83,44 +128,112 @@ func (v *Validator) HasBeenIssuedBy(issuers []string) bool {
 	return false
 }
 
+// HasBeenIssuedBefore checks the `iat` claim is before the now timestamp.
 func (v *Validator) HasBeenIssuedBefore(now int64) bool {
-	if val, ok := v.claims["iat"]; ok {
-		if vv, ok := val.(float64); ok {
-			if now+v.leeway > int64(vv) {
-				return true
-			}
-		}
+	return v.hasBeenIssuedBefore(time.Unix(now, 0))
+}
+
+// HasBeenIssuedBeforeNow checks the `iat` claim with the validator clock.
+func (v *Validator) HasBeenIssuedBeforeNow() bool {
+	return v.hasBeenIssuedBefore(v.now())
+}
+
+// IsMinimumTimeBefore checks the `nbf` claim is before the now timestamp.
+func (v *Validator) IsMinimumTimeBefore(now int64) bool {
+	return v.isMinimumTimeBefore(time.Unix(now, 0))
+}
 
+// IsMinimumTimeBeforeNow checks the `nbf` claim with the validator clock.
+func (v *Validator) IsMinimumTimeBeforeNow() bool {
+	return v.isMinimumTimeBefore(v.now())
+}
+
+// IsExpired checks the `exp` claim is not after the now timestamp.
+func (v *Validator) IsExpired(now int64) bool {
+	return v.isExpired(time.Unix(now, 0))
+}
+
+// IsExpiredNow checks the `exp` claim with the validator clock.
+func (v *Validator) IsExpiredNow() bool {
+	return v.isExpired(v.now())
+}
+
+func (v *Validator) hasBeenIssuedBefore(now time.Time) bool {
+	iat, ok, err := v.claimTime("iat")
+	if err != nil {
 		return false
 	}
+	if !ok {
+		return true
+	}
 
-	return true
+	return now.Add(v.claimLeeway("iat")).After(iat)
 }
 
-func (v *Validator) IsMinimumTimeBefore(now int64) bool {
-	if val, ok := v.claims["nbf"]; ok {
-		if vv, ok := val.(float64); ok {
-			if now+v.leeway > int64(vv) {
-				return true
-			}
-		}
+func (v *Validator) isMinimumTimeBefore(now time.Time) bool {
+	nbf, ok, err := v.claimTime("nbf")
+	if err != nil {
+		return false
+	}
+	if !ok {
+		return true
+	}
+
+	return now.Add(v.claimLeeway("nbf")).After(nbf)
+}
 
+func (v *Validator) isExpired(now time.Time) bool {
+	exp, ok, err := v.claimTime("exp")
+	if err != nil {
+		return true
+	}
+	if !ok {
 		return false
 	}
 
-	return true
+	return !now.Add(-v.claimLeeway("exp")).Before(exp)
 }
 
-func (v *Validator) IsExpired(now int64) bool {
-	if val, ok := v.claims["exp"]; ok {
-		if vv, ok := val.(float64); ok {
-			if now-v.leeway < int64(vv) {
-				return false
-			}
-		}
+// return the validator current time
+func (v *Validator) now() time.Time {
+	return clockNow(v.clock)
+}
 
-		return true
+// return the claim leeway, or the validator leeway
+func (v *Validator) claimLeeway(name string) time.Duration {
+	if leeway, ok := v.claimLeeways[name]; ok {
+		return leeway
 	}
 
-	return false
+	return v.leeway
+}
+
+// return the claim time and if the claim exists. The `json.Number`,
+// `float64`, `int64`, `int` and numeric string values are supported,
+// the values which are not finite are invalid.
+func (v *Validator) claimTime(name string) (time.Time, bool, error) {
+	val, ok := v.claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	switch vv := val.(type) {
+	case int:
+		val = int64(vv)
+	case string:
+		// the NaN and Inf values are rejected with the number date
+		val = json.Number(vv)
+	}
+
+	date, err := MapClaims{name: val}.GetNumericDate(name)
+	if err != nil {
+		return time.Time{}, true, err
+	}
+
+	// the zero time value
+	if date == nil {
+		return time.Unix(0, 0), true, nil
+	}
+
+	return date.Time, true, nil
 }
@@ -0,0 +1,310 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+var (
+	ErrTokenTypeInvalid   = errors.New("go-jwt: token type invalid")
+	ErrTokenClaimsInvalid = errors.New("go-jwt: refresh token claims invalid")
+)
+
+const (
+	// the `token_use` claim of the access tokens
+	AccessTokenUse = "access"
+
+	// the `token_use` claim of the refresh tokens
+	RefreshTokenUse = "refresh"
+
+	// default access token lifetime
+	DefaultAccessTTL = 15 * time.Minute
+
+	// default refresh token lifetime
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+
+	// the token type claim
+	tokenUseClaim = "token_use"
+
+	// the refresh token family id claim
+	familyClaim = "fid"
+)
+
+// TokenPair is an access token and a refresh token
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+type config struct {
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	issuer     string
+	audience   []string
+	clock      jwt.Clock
+}
+
+// Manager issues the access and refresh token pairs and rotates the
+// refresh tokens. Every refresh token can be used only once, when a
+// used refresh token is used again all the tokens of its family are
+// revoked, so a stolen refresh token can be used by one party at most.
+type Manager[S any, V any] struct {
+	jwt       *jwt.JWT[S, V]
+	signKey   S
+	verifyKey V
+	store     Store
+
+	config
+}
+
+// NewManager returns a new Manager, the tokens are signed with the JWT.
+func NewManager[S any, V any](j *jwt.JWT[S, V], signKey S, verifyKey V, store Store, options ...Option) *Manager[S, V] {
+	m := &Manager[S, V]{
+		jwt:       j,
+		signKey:   signKey,
+		verifyKey: verifyKey,
+		store:     store,
+		config: config{
+			accessTTL:  DefaultAccessTTL,
+			refreshTTL: DefaultRefreshTTL,
+			clock:      j.GetClock(),
+		},
+	}
+
+	// Loop through our manager options and apply them
+	for _, option := range options {
+		option(&m.config)
+	}
+
+	// the memory store expires the families with the manager clock
+	if s, ok := store.(*MemoryStore); ok {
+		s.defaultClock(m.clock)
+	}
+
+	return m
+}
+
+// Issue issues a token pair of a new family for the subject,
+// the claims are added to every access token of the family.
+func (m *Manager[S, V]) Issue(ctx context.Context, subject string, claims map[string]any) (TokenPair, error) {
+	familyID, err := jwt.RandomID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := m.clock.Now()
+
+	record, refreshToken, err := m.refreshToken(now, subject, familyID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	record.Claims = maps.Clone(claims)
+
+	accessToken, accessExpiresAt, err := m.accessToken(now, subject, claims)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if err := m.store.Create(ctx, record); err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+// Rotate verifies the refresh token and returns a new token pair of
+// the same family. When the refresh token has been used, the family
+// is revoked and ErrTokenReused is returned. The tokens are signed
+// before the refresh token is marked as used, so a signing error
+// does not use up the refresh token.
+func (m *Manager[S, V]) Rotate(ctx context.Context, refreshToken string) (TokenPair, error) {
+	id, subject, familyID, err := m.parseRefreshToken(refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	record, err := m.store.Get(ctx, id)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := m.clock.Now()
+
+	accessToken, accessExpiresAt, err := m.accessToken(now, subject, record.Claims)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	next, nextToken, err := m.refreshToken(now, subject, familyID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if _, err := m.store.Rotate(ctx, id, next); err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			return TokenPair{}, errors.Join(err, m.store.RevokeFamily(ctx, familyID))
+		}
+
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     nextToken,
+		RefreshExpiresAt: next.ExpiresAt,
+	}, nil
+}
+
+// Revoke verifies the refresh token and revokes its family.
+func (m *Manager[S, V]) Revoke(ctx context.Context, refreshToken string) error {
+	_, _, familyID, err := m.parseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	return m.store.RevokeFamily(ctx, familyID)
+}
+
+// ParseAccess parses and validates an access token, the
+// refresh tokens are rejected with ErrTokenTypeInvalid.
+func (m *Manager[S, V]) ParseAccess(accessToken string) (*jwt.Token, error) {
+	t, err := m.jwt.Parse(accessToken, m.verifyKey, jwt.ParserOption{
+		Issuer:   m.issuer,
+		Audience: m.audience,
+		Clock:    m.clock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTokenUse(t, AccessTokenUse); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// return the refresh token `jti`, `sub` and family id
+func (m *Manager[S, V]) parseRefreshToken(refreshToken string) (id, subject, familyID string, err error) {
+	t, err := m.jwt.Parse(refreshToken, m.verifyKey, jwt.ParserOption{
+		Issuer:         m.issuer,
+		RequiredClaims: []string{"jti", "sub", "exp"},
+		Clock:          m.clock,
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	claims, err := t.GetClaims()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if use, _ := claims.GetString(tokenUseClaim); use != RefreshTokenUse {
+		return "", "", "", ErrTokenTypeInvalid
+	}
+
+	id, _ = claims.GetString("jti")
+	subject, _ = claims.GetSubject()
+	familyID, _ = claims.GetString(familyClaim)
+	if id == "" || subject == "" || familyID == "" {
+		return "", "", "", ErrTokenClaimsInvalid
+	}
+
+	return id, subject, familyID, nil
+}
+
+// return a new refresh token and its record
+func (m *Manager[S, V]) refreshToken(now time.Time, subject, familyID string) (Record, string, error) {
+	id, err := jwt.RandomID()
+	if err != nil {
+		return Record{}, "", err
+	}
+
+	expiresAt := now.Add(m.refreshTTL)
+
+	b := m.jwt.Build().
+		RelatedTo(subject).
+		IdentifiedBy(id).
+		IssuedAt(jwt.NewNumericDate(now)).
+		ExpiresAt(jwt.NewNumericDate(expiresAt)).
+		WithClaim(tokenUseClaim, RefreshTokenUse).
+		WithClaim(familyClaim, familyID)
+	if m.issuer != "" {
+		b.IssuedBy(m.issuer)
+	}
+
+	tokenString, err := signedString(b, m.signKey)
+	if err != nil {
+		return Record{}, "", err
+	}
+
+	return Record{
+		ID:        id,
+		FamilyID:  familyID,
+		Subject:   subject,
+		ExpiresAt: expiresAt,
+	}, tokenString, nil
+}
+
+// return a new access token with the claims
+func (m *Manager[S, V]) accessToken(now time.Time, subject string, claims map[string]any) (string, time.Time, error) {
+	expiresAt := now.Add(m.accessTTL)
+
+	b := m.jwt.Build().
+		WithClaims(jwt.MapClaims(claims)).
+		WithClaim(tokenUseClaim, AccessTokenUse).
+		RelatedTo(subject).
+		WithRandomID().
+		IssuedAt(jwt.NewNumericDate(now)).
+		ExpiresAt(jwt.NewNumericDate(expiresAt))
+	if m.issuer != "" {
+		b.IssuedBy(m.issuer)
+	}
+	if len(m.audience) > 0 {
+		b.PermittedFor(jwt.NewClaimStrings(m.audience, len(m.audience) == 1))
+	}
+
+	tokenString, err := signedString(b, m.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// return the signed token string of the builder
+func signedString[S any](b *jwt.Builder[S], key S) (string, error) {
+	t, err := b.GetToken(key)
+	if err != nil {
+		return "", err
+	}
+
+	return t.SignedString()
+}
+
+// check the token `token_use` claim
+func checkTokenUse(t *jwt.Token, use string) error {
+	claims, err := t.GetClaims()
+	if err != nil {
+		return err
+	}
+
+	if got, _ := claims.GetString(tokenUseClaim); got != use {
+		return ErrTokenTypeInvalid
+	}
+
+	return nil
+}
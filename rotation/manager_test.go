@@ -0,0 +1,275 @@
+package rotation
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+func newTestManager(t *testing.T, clock jwt.Clock) (*Manager[[]byte, []byte], *MemoryStore) {
+	key := []byte("test-key")
+	store := NewMemoryStore()
+
+	m := NewManager(jwt.SigningMethodHS256.New(), key, key, store,
+		WithIssuer("issuer"),
+		WithAudience("example.com"),
+		WithAccessTTL(time.Minute),
+		WithRefreshTTL(time.Hour),
+		WithClock(clock),
+	)
+
+	return m, store
+}
+
+func Test_Manager(t *testing.T) {
+	ctx := t.Context()
+	clock := jwt.NewFrozenClock(time.Unix(1700000000, 0))
+
+	m, store := newTestManager(t, clock)
+
+	pair, err := m.Issue(ctx, "user", map[string]any{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !pair.AccessExpiresAt.Equal(time.Unix(1700000060, 0)) {
+		t.Errorf("AccessExpiresAt got %s", pair.AccessExpiresAt)
+	}
+	if !pair.RefreshExpiresAt.Equal(time.Unix(1700003600, 0)) {
+		t.Errorf("RefreshExpiresAt got %s", pair.RefreshExpiresAt)
+	}
+
+	access, err := m.ParseAccess(pair.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := access.GetClaims()
+	if role, _ := claims.GetString("role"); role != "admin" {
+		t.Errorf("role got %s, want %s", role, "admin")
+	}
+	if sub, _ := claims.GetSubject(); sub != "user" {
+		t.Errorf("sub got %s, want %s", sub, "user")
+	}
+
+	// the token types are not mixed
+	if _, err := m.ParseAccess(pair.RefreshToken); !errors.Is(err, jwt.ErrJWTTokenInvalidAudience) {
+		t.Errorf("ParseAccess got %v, want %v", err, jwt.ErrJWTTokenInvalidAudience)
+	}
+
+	key := []byte("test-key")
+	noAudience := NewManager(jwt.SigningMethodHS256.New(), key, key, store, WithClock(clock))
+	if _, err := noAudience.ParseAccess(pair.RefreshToken); !errors.Is(err, ErrTokenTypeInvalid) {
+		t.Errorf("ParseAccess got %v, want %v", err, ErrTokenTypeInvalid)
+	}
+	if _, err := m.Rotate(ctx, pair.AccessToken); !errors.Is(err, ErrTokenTypeInvalid) {
+		t.Errorf("Rotate got %v, want %v", err, ErrTokenTypeInvalid)
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	if _, err := m.ParseAccess(pair.AccessToken); !errors.Is(err, jwt.ErrJWTTokenExpired) {
+		t.Errorf("ParseAccess got %v, want %v", err, jwt.ErrJWTTokenExpired)
+	}
+
+	pair2, err := m.Rotate(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	access, err = m.ParseAccess(pair2.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the claims are kept in the family
+	claims, _ = access.GetClaims()
+	if role, _ := claims.GetString("role"); role != "admin" {
+		t.Errorf("role got %s, want %s", role, "admin")
+	}
+
+	pair3, err := m.Rotate(ctx, pair2.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if store.Len() != 3 {
+		t.Errorf("store Len got %d, want %d", store.Len(), 3)
+	}
+
+	// the reused token revokes the family
+	if _, err := m.Rotate(ctx, pair.RefreshToken); !errors.Is(err, ErrTokenReused) {
+		t.Errorf("Rotate got %v, want %v", err, ErrTokenReused)
+	}
+	if _, err := m.Rotate(ctx, pair3.RefreshToken); !errors.Is(err, ErrFamilyRevoked) {
+		t.Errorf("Rotate got %v, want %v", err, ErrFamilyRevoked)
+	}
+
+	// the other families are not revoked
+	other, err := m.Issue(ctx, "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Rotate(ctx, other.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Manager_Revoke(t *testing.T) {
+	ctx := t.Context()
+	clock := jwt.NewFrozenClock(time.Unix(1700000000, 0))
+
+	m, _ := newTestManager(t, clock)
+
+	pair, err := m.Issue(ctx, "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Revoke(ctx, pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Rotate(ctx, pair.RefreshToken); !errors.Is(err, ErrFamilyRevoked) {
+		t.Errorf("Rotate got %v, want %v", err, ErrFamilyRevoked)
+	}
+
+	pair, err = m.Issue(ctx, "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(2 * time.Hour)
+
+	if _, err := m.Rotate(ctx, pair.RefreshToken); !errors.Is(err, jwt.ErrJWTTokenExpired) {
+		t.Errorf("Rotate got %v, want %v", err, jwt.ErrJWTTokenExpired)
+	}
+
+	// signed with an other key
+	other := NewManager(jwt.SigningMethodHS256.New(), []byte("other-key"), []byte("other-key"), NewMemoryStore())
+	if _, err := other.Rotate(ctx, pair.RefreshToken); !errors.Is(err, jwt.ErrJWTTokenSignatureInvalid) {
+		t.Errorf("Rotate got %v, want %v", err, jwt.ErrJWTTokenSignatureInvalid)
+	}
+}
+
+func Test_Manager_RotateSignFail(t *testing.T) {
+	ctx := t.Context()
+	clock := jwt.NewFrozenClock(time.Unix(1700000000, 0))
+
+	m, store := newTestManager(t, clock)
+
+	pair, err := m.Issue(ctx, "user", map[string]any{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the access token claims can not be encoded
+	for id, record := range store.records {
+		record.Claims = map[string]any{"bad": make(chan int)}
+		store.records[id] = record
+	}
+
+	if _, err := m.Rotate(ctx, pair.RefreshToken); err == nil {
+		t.Fatal("Rotate should fail")
+	}
+
+	// the refresh token is not used up
+	for id, record := range store.records {
+		if record.Used() {
+			t.Fatalf("record %s should not be used", id)
+		}
+
+		record.Claims = map[string]any{"role": "admin"}
+		store.records[id] = record
+	}
+
+	if _, err := m.Rotate(ctx, pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Manager_StoreClock(t *testing.T) {
+	ctx := t.Context()
+	clock := jwt.NewFrozenClock(time.Unix(1700000000, 0))
+
+	m, store := newTestManager(t, clock)
+
+	pair, err := m.Issue(ctx, "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the store cleanup uses the manager clock
+	clock.Advance(2 * time.Minute)
+
+	if _, err := m.Issue(ctx, "user2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Rotate(ctx, pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(2 * time.Hour)
+
+	if _, err := m.Issue(ctx, "user3", nil); err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Len got %d, want %d", store.Len(), 1)
+	}
+
+	// the store clock is not replaced
+	other := jwt.NewFrozenClock(time.Unix(1800000000, 0))
+	store2 := NewMemoryStore().WithClock(other)
+	NewManager(jwt.SigningMethodHS256.New(), []byte("test-key"), []byte("test-key"), store2, WithClock(clock))
+	if store2.clock != other {
+		t.Error("store clock should not be replaced")
+	}
+}
+
+func Test_Manager_RotateConcurrent(t *testing.T) {
+	ctx := t.Context()
+
+	m, _ := newTestManager(t, jwt.NewFrozenClock(time.Unix(1700000000, 0)))
+
+	pair, err := m.Issue(ctx, "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := m.Rotate(ctx, pair.RefreshToken)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var ok, reused int
+	for err := range errs {
+		switch {
+		case err == nil:
+			ok++
+		case errors.Is(err, ErrTokenReused), errors.Is(err, ErrFamilyRevoked):
+			reused++
+		default:
+			t.Error(err)
+		}
+	}
+
+	if ok != 1 || reused != 9 {
+		t.Errorf("Rotate got %d ok and %d reused, want 1 and 9", ok, reused)
+	}
+}
@@ -0,0 +1,45 @@
+package rotation
+
+import (
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+type Option func(*config)
+
+// WithAccessTTL sets the access token lifetime.
+func WithAccessTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.accessTTL = ttl
+	}
+}
+
+// WithRefreshTTL sets the refresh token lifetime.
+func WithRefreshTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.refreshTTL = ttl
+	}
+}
+
+// WithIssuer sets the `iss` claim of the tokens, it is
+// checked when the refresh token is parsed.
+func WithIssuer(issuer string) Option {
+	return func(c *config) {
+		c.issuer = issuer
+	}
+}
+
+// WithAudience sets the `aud` claim of the access tokens.
+func WithAudience(audience ...string) Option {
+	return func(c *config) {
+		c.audience = audience
+	}
+}
+
+// WithClock sets the clock used by the tokens time claims.
+func WithClock(clock jwt.Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
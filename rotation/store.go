@@ -0,0 +1,235 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+var (
+	ErrTokenNotFound = errors.New("go-jwt: refresh token not found")
+	ErrTokenReused   = errors.New("go-jwt: refresh token reused")
+	ErrFamilyRevoked = errors.New("go-jwt: refresh token family revoked")
+)
+
+// Record is a refresh token saved in the store
+type Record struct {
+	// the refresh token `jti` claim
+	ID string
+
+	// the family id, all the refresh tokens rotated from
+	// the same issued token have the same family id
+	FamilyID string
+
+	// the `sub` claim
+	Subject string
+
+	// the access token claims, copied when the token is rotated
+	Claims map[string]any
+
+	// the refresh token expiration time
+	ExpiresAt time.Time
+
+	// the `jti` of the refresh token which replaced this token,
+	// empty when the token has not been used
+	ReplacedBy string
+}
+
+// return true when the refresh token has been used
+func (r Record) Used() bool {
+	return r.ReplacedBy != ""
+}
+
+// Store saves the refresh token families
+type Store interface {
+	// Create saves the first refresh token of a new family.
+	Create(ctx context.Context, record Record) error
+
+	// Get returns the record of the refresh token, it returns
+	// ErrTokenNotFound when the token is unknown.
+	Get(ctx context.Context, id string) (Record, error)
+
+	// Rotate marks the refresh token as used and saves the next
+	// refresh token of the family in one atomic step, the next
+	// FamilyID and Claims are copied from the token. It returns
+	// ErrTokenNotFound when the token is unknown, ErrFamilyRevoked
+	// when the family has been revoked and ErrTokenReused when the
+	// token has been used, the record of the token is returned
+	// with ErrTokenReused.
+	Rotate(ctx context.Context, id string, next Record) (Record, error)
+
+	// RevokeFamily revokes all the refresh tokens of the family.
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+// the memory store cleanup interval
+const memoryCleanupInterval = time.Minute
+
+// MemoryStore is a Store in memory, the expired
+// families are removed when new tokens are saved.
+type MemoryStore struct {
+	mu       sync.Mutex
+	records  map[string]Record
+	families map[string]*memoryFamily
+
+	clock       jwt.Clock
+	lastCleanup time.Time
+}
+
+// a family in the memory store
+type memoryFamily struct {
+	ids       []string
+	expiresAt time.Time
+	revoked   bool
+}
+
+// NewMemoryStore returns a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:  make(map[string]Record),
+		families: make(map[string]*memoryFamily),
+	}
+}
+
+// with the clock for the families expiration, jwt.SystemClock is used
+// when nil. The Manager sets its clock when the store has no clock.
+func (s *MemoryStore) WithClock(clock jwt.Clock) *MemoryStore {
+	s.clock = clock
+	return s
+}
+
+// set the clock when the store has no clock
+func (s *MemoryStore) defaultClock(clock jwt.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.clock == nil {
+		s.clock = clock
+	}
+}
+
+// Create implements the Store interface.
+func (s *MemoryStore) Create(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanup()
+
+	s.families[record.FamilyID] = &memoryFamily{}
+	s.add(record)
+
+	return nil
+}
+
+// Get implements the Store interface.
+func (s *MemoryStore) Get(ctx context.Context, id string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return Record{}, ErrTokenNotFound
+	}
+
+	record.Claims = maps.Clone(record.Claims)
+
+	return record, nil
+}
+
+// Rotate implements the Store interface.
+func (s *MemoryStore) Rotate(ctx context.Context, id string, next Record) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return Record{}, ErrTokenNotFound
+	}
+
+	family, ok := s.families[record.FamilyID]
+	if !ok {
+		return Record{}, ErrTokenNotFound
+	}
+
+	if family.revoked {
+		return record, ErrFamilyRevoked
+	}
+	if record.Used() {
+		return record, ErrTokenReused
+	}
+
+	s.cleanup()
+
+	record.ReplacedBy = next.ID
+	s.records[id] = record
+
+	next.FamilyID = record.FamilyID
+	next.Claims = record.Claims
+	s.add(next)
+
+	return record, nil
+}
+
+// RevokeFamily implements the Store interface.
+func (s *MemoryStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if family, ok := s.families[familyID]; ok {
+		family.revoked = true
+	}
+
+	return nil
+}
+
+// Len returns the number of the saved refresh tokens.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.records)
+}
+
+// save the record to its family
+func (s *MemoryStore) add(record Record) {
+	record.Claims = maps.Clone(record.Claims)
+	s.records[record.ID] = record
+
+	family := s.families[record.FamilyID]
+	family.ids = append(family.ids, record.ID)
+	if record.ExpiresAt.After(family.expiresAt) {
+		family.expiresAt = record.ExpiresAt
+	}
+}
+
+// remove the families which all tokens are expired,
+// the revoked families are kept until they are expired
+func (s *MemoryStore) cleanup() {
+	clock := s.clock
+	if clock == nil {
+		clock = jwt.SystemClock
+	}
+
+	now := clock.Now()
+	if now.Sub(s.lastCleanup) < memoryCleanupInterval {
+		return
+	}
+
+	s.lastCleanup = now
+
+	for familyID, family := range s.families {
+		if now.Before(family.expiresAt) {
+			continue
+		}
+
+		for _, id := range family.ids {
+			delete(s.records, id)
+		}
+
+		delete(s.families, familyID)
+	}
+}
@@ -0,0 +1,84 @@
+package rotation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deatil/go-jwt/jwt"
+)
+
+func Test_MemoryStore(t *testing.T) {
+	ctx := t.Context()
+	now := time.Unix(1700000000, 0)
+	clock := jwt.NewFrozenClock(now)
+
+	s := NewMemoryStore().WithClock(clock)
+
+	err := s.Create(ctx, Record{
+		ID:        "id1",
+		FamilyID:  "family1",
+		Subject:   "user",
+		Claims:    map[string]any{"role": "admin"},
+		ExpiresAt: now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Get(ctx, "id0"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Get got %v, want %v", err, ErrTokenNotFound)
+	}
+
+	got, err := s.Get(ctx, "id1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subject != "user" || got.Claims["role"] != "admin" || got.Used() {
+		t.Errorf("Get got %+v", got)
+	}
+
+	if _, err := s.Rotate(ctx, "id0", Record{ID: "id2"}); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Rotate got %v, want %v", err, ErrTokenNotFound)
+	}
+
+	record, err := s.Rotate(ctx, "id1", Record{ID: "id2", ExpiresAt: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ReplacedBy != "id2" {
+		t.Errorf("ReplacedBy got %s, want %s", record.ReplacedBy, "id2")
+	}
+
+	next := s.records["id2"]
+	if next.FamilyID != "family1" || next.Claims["role"] != "admin" {
+		t.Errorf("next record got %+v", next)
+	}
+
+	if _, err := s.Rotate(ctx, "id1", Record{ID: "id3"}); !errors.Is(err, ErrTokenReused) {
+		t.Errorf("Rotate got %v, want %v", err, ErrTokenReused)
+	}
+
+	// the family is removed when all tokens are expired
+	now = now.Add(90 * time.Minute)
+	clock.Set(now)
+
+	err = s.Create(ctx, Record{ID: "id4", FamilyID: "family2", ExpiresAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len got %d, want %d", s.Len(), 3)
+	}
+
+	now = now.Add(time.Hour)
+	clock.Set(now)
+
+	err = s.Create(ctx, Record{ID: "id5", FamilyID: "family3", ExpiresAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len got %d, want %d", s.Len(), 1)
+	}
+}